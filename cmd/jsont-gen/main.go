@@ -0,0 +1,102 @@
+// Command jsont-gen generates Go struct definitions from JSON samples.
+//
+//	jsont-gen -name User -package api user1.json user2.json > user.go
+//
+// Each argument is a file holding a JSON object, or an array of objects
+// that are each used as a sample. With no arguments the sample is read
+// from stdin. Keys missing from some samples become optional pointer
+// fields.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+func main() {
+	name := flag.String("name", "Payload", "name of the top level type")
+	pkg := flag.String("package", "main", "package clause of the generated file")
+	out := flag.String("o", "", "write to this file instead of stdout")
+	flag.Parse()
+
+	if err := run(*name, *pkg, *out, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "jsont-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(name, pkg, out string, files []string) error {
+	var samples []jsont.Object
+
+	if len(files) == 0 {
+		b, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+
+		if samples, err = parseSamples("stdin", b); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		parsed, err := parseSamples(file, b)
+		if err != nil {
+			return err
+		}
+		samples = append(samples, parsed...)
+	}
+
+	src, err := jsont.GenerateStructs(name, samples...)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jsont-gen. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	buf.Write(src)
+
+	if out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+
+	return os.WriteFile(out, buf.Bytes(), 0644)
+}
+
+// parseSamples reads a JSON object, or an array of them
+func parseSamples(source string, b []byte) ([]jsont.Object, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("%s: %v", source, err)
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return []jsont.Object{jsont.Set(val)}, nil
+	case []interface{}:
+		samples := make([]jsont.Object, 0, len(val))
+		for i, elem := range val {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s: element %d is not an object", source, i)
+			}
+			samples = append(samples, jsont.Set(m))
+		}
+
+		return samples, nil
+	}
+
+	return nil, fmt.Errorf("%s: samples must be JSON objects", source)
+}
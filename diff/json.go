@@ -0,0 +1,169 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dedwardstech/test/internal/number"
+	"github.com/dedwardstech/test/internal/path"
+)
+
+// JSONChange is a single difference between two JSON documents. A value that
+// only exists in one document has InA or InB set to false.
+type JSONChange struct {
+	Path     string
+	A, B     interface{}
+	InA, InB bool
+}
+
+func (c JSONChange) String() string {
+	switch {
+	case !c.InB:
+		return fmt.Sprintf("%s: only in A: %s", c.Path, formatJSON(c.A))
+	case !c.InA:
+		return fmt.Sprintf("%s: only in B: %s", c.Path, formatJSON(c.B))
+	}
+
+	return fmt.Sprintf("%s: A has %s; B has %s", c.Path, formatJSON(c.A), formatJSON(c.B))
+}
+
+// JSONDiff is used to hold the results of diffing two JSON documents
+type JSONDiff []JSONChange
+
+func (d JSONDiff) Error() string {
+	lines := make([]string, len(d))
+	for i, c := range d {
+		lines[i] = c.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// formatJSON writes a value the way it would look in a payload, so strings
+// are quoted and nested objects stay readable.
+func formatJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
+
+// JSON calculates the structural differences between two decoded JSON
+// documents. Every change is reported with the property path it was found
+// at, such as "users[1].name". Like Slice, the result is an error so it can
+// be returned straight from a test helper.
+//
+// Numbers are compared by value, whatever Go type holds them. Arrays are
+// compared index by index.
+//
+//	a := {"name": "bob", "tags": ["x"]}
+//	b := {"name": "al", "tags": ["x", "y"]}
+//
+//	diff(a, b) = name: A has "bob"; B has "al"
+//	             tags[1]: only in B: "y"
+func JSON(a, b interface{}) error {
+	changes := diffJSON("", a, b)
+	if len(changes) > 0 {
+		return JSONDiff(changes)
+	}
+
+	return nil
+}
+
+func diffJSON(p string, a, b interface{}) []JSONChange {
+	am, aIsMap := jsonMap(a)
+	bm, bIsMap := jsonMap(b)
+	if aIsMap && bIsMap {
+		return diffMaps(p, am, bm)
+	}
+
+	as, aIsSlice := a.([]interface{})
+	bs, bIsSlice := b.([]interface{})
+	if aIsSlice && bIsSlice {
+		return diffSlices(p, as, bs)
+	}
+
+	if x, ok := number.Float64(a); ok {
+		if y, ok := number.Float64(b); ok && x == y {
+			return nil
+		}
+	} else if reflect.DeepEqual(a, b) {
+		return nil
+	}
+
+	return []JSONChange{{Path: p, A: a, B: b, InA: true, InB: true}}
+}
+
+func diffMaps(p string, a, b map[string]interface{}) []JSONChange {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []JSONChange
+	for _, k := range keys {
+		av, inA := a[k]
+		bv, inB := b[k]
+		keyPath := path.Key(p, k)
+
+		if inA && inB {
+			changes = append(changes, diffJSON(keyPath, av, bv)...)
+			continue
+		}
+
+		changes = append(changes, JSONChange{Path: keyPath, A: av, B: bv, InA: inA, InB: inB})
+	}
+
+	return changes
+}
+
+func diffSlices(p string, a, b []interface{}) []JSONChange {
+	var changes []JSONChange
+
+	for i := 0; i < len(a) || i < len(b); i++ {
+		indexPath := path.Index(p, i)
+
+		switch {
+		case i >= len(b):
+			changes = append(changes, JSONChange{Path: indexPath, A: a[i], InA: true})
+		case i >= len(a):
+			changes = append(changes, JSONChange{Path: indexPath, B: b[i], InB: true})
+		default:
+			changes = append(changes, diffJSON(indexPath, a[i], b[i])...)
+		}
+	}
+
+	return changes
+}
+
+// jsonMap accepts any map with string keys, which covers named map types
+// like jsont.Object without importing them.
+func jsonMap(v interface{}) (map[string]interface{}, bool) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String || rv.Type().Elem().Kind() != reflect.Interface {
+		return nil, false
+	}
+
+	m := make(map[string]interface{}, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		m[iter.Key().String()] = iter.Value().Interface()
+	}
+
+	return m, true
+}
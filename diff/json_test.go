@@ -0,0 +1,79 @@
+package diff
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func Test_JSON(tt *testing.T) {
+	type testArgs struct {
+		a, b interface{}
+	}
+
+	tests := []struct {
+		name     string
+		args     testArgs
+		expected error
+	}{
+		{
+			name: "returns no error for equal documents",
+			args: testArgs{
+				a: map[string]interface{}{"a": []interface{}{1.0, "x"}, "b": nil},
+				b: map[string]interface{}{"a": []interface{}{1.0, "x"}, "b": nil},
+			},
+			expected: nil,
+		},
+		{
+			name: "compares numbers by value",
+			args: testArgs{
+				a: map[string]interface{}{"n": 1},
+				b: map[string]interface{}{"n": 1.0},
+			},
+			expected: nil,
+		},
+		{
+			name: "reports changed values with their paths",
+			args: testArgs{
+				a: map[string]interface{}{"user": map[string]interface{}{"name": "bob"}},
+				b: map[string]interface{}{"user": map[string]interface{}{"name": "al"}},
+			},
+			expected: errors.New(`user.name: A has "bob"; B has "al"`),
+		},
+		{
+			name: "reports keys only in one document",
+			args: testArgs{
+				a: map[string]interface{}{"a": true},
+				b: map[string]interface{}{"b": map[string]interface{}{"c": 1}},
+			},
+			expected: errors.New("a: only in A: true\nb: only in B: {\"c\":1}"),
+		},
+		{
+			name: "diffs arrays by index",
+			args: testArgs{
+				a: map[string]interface{}{"tags": []interface{}{"x", "y"}},
+				b: map[string]interface{}{"tags": []interface{}{"x", "z", "w"}},
+			},
+			expected: errors.New("tags[1]: A has \"y\"; B has \"z\"\ntags[2]: only in B: \"w\""),
+		},
+		{
+			name: "reports type changes",
+			args: testArgs{
+				a: map[string]interface{}{"id": "1"},
+				b: map[string]interface{}{"id": 1},
+			},
+			expected: errors.New(`id: A has "1"; B has 1`),
+		},
+	}
+
+	for _, tc := range tests {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := JSON(tc.args.a, tc.args.b)
+
+			if e := compare.Errors(tc.expected, err); e != nil {
+				t.Error(e)
+			}
+		})
+	}
+}
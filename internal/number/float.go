@@ -0,0 +1,45 @@
+// Package number normalizes the many ways a JSON number can be
+// represented in Go.
+package number
+
+import "encoding/json"
+
+// Float64 converts any of Go's numeric types, or a json.Number, to
+// a float64. The second return value is false when v isn't a number.
+func Float64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+
+		return f, true
+	}
+
+	return 0, false
+}
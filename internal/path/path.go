@@ -0,0 +1,29 @@
+// Package path builds the property paths used in error messages, such as
+// "users[0].name".
+package path
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Key appends an object key to a property path. Keys that are empty or
+// contain "." or "[" are quoted in brackets, like
+// `labels["app.kubernetes.io/name"]`, so the path can be split back into
+// its keys.
+func Key(parent, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[") {
+		return parent + "[" + strconv.Quote(key) + "]"
+	}
+
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
+
+// Index appends an array index to a property path
+func Index(parent string, i int) string {
+	return parent + "[" + strconv.Itoa(i) + "]"
+}
@@ -0,0 +1,208 @@
+package jsont
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// Canonical encodes the Object as RFC 8785 canonical JSON. Equal Objects
+// always encode to the same bytes, which makes the output safe to hash,
+// sign or compare byte for byte.
+//
+// Canonical panics if the Object holds a value that has no canonical JSON
+// form, like NaN or a string that isn't valid UTF-8. Use MarshalCanonical
+// to get an error instead.
+func (o Object) Canonical() []byte {
+	b, err := MarshalCanonical(o)
+	if err != nil {
+		panic(err)
+	}
+
+	return b
+}
+
+// MarshalCanonical encodes v as RFC 8785 (JSON Canonicalization Scheme)
+// JSON. Object keys are sorted by their UTF-16 code units, numbers are
+// written the way ECMAScript formats them and strings only escape what
+// JSON requires. Values that aren't already decoded JSON, such as structs,
+// are run through encoding/json first.
+func MarshalCanonical(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeCanonical(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeCanonical(buf *bytes.Buffer, v interface{}) error {
+	if m, ok := asMap(v); ok {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return lessUTF16(keys[i], keys[j])
+		})
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeCanonicalString(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+
+			if err := writeCanonical(buf, m[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+		return nil
+	}
+
+	if n, ok := number.Float64(v); ok {
+		s, err := canonicalNumber(n)
+		if err != nil {
+			return err
+		}
+
+		buf.WriteString(s)
+		return nil
+	}
+
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+	case bool:
+		buf.WriteString(strconv.FormatBool(val))
+	case string:
+		return writeCanonicalString(buf, val)
+	case []interface{}:
+		buf.WriteByte('[')
+		for i, elem := range val {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			if err := writeCanonical(buf, elem); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+	default:
+		// Anything else is whatever encoding/json makes of it
+		b, err := json.Marshal(val)
+		if err != nil {
+			return err
+		}
+
+		var decoded interface{}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(&decoded); err != nil {
+			return err
+		}
+
+		return writeCanonical(buf, decoded)
+	}
+
+	return nil
+}
+
+// canonicalNumber formats a number the way ECMAScript's Number.toString
+// does, as RFC 8785 requires.
+func canonicalNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.Errorf("%v has no JSON representation", f)
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign, f = "-", -f
+	}
+
+	format := byte('e')
+	if f >= 1e-6 && f < 1e21 {
+		format = 'f'
+	}
+
+	s := strconv.FormatFloat(f, format, -1, 64)
+
+	// Go pads exponents to two digits, ECMAScript doesn't
+	if i := strings.IndexByte(s, 'e'); i > 0 && s[i+2] == '0' {
+		s = s[:i+2] + s[i+3:]
+	}
+
+	return sign + s, nil
+}
+
+func writeCanonicalString(buf *bytes.Buffer, s string) error {
+	if !utf8.ValidString(s) {
+		return errors.Errorf("%q is not valid UTF-8", s)
+	}
+
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				buf.WriteString(`\u00`)
+				buf.WriteByte("0123456789abcdef"[r>>4])
+				buf.WriteByte("0123456789abcdef"[r&0xf])
+				continue
+			}
+
+			buf.WriteRune(r)
+		}
+	}
+	buf.WriteByte('"')
+
+	return nil
+}
+
+// lessUTF16 orders strings by their UTF-16 code units. It differs from Go's
+// byte order for characters outside the Basic Multilingual Plane.
+func lessUTF16(a, b string) bool {
+	ua, ub := utf16.Encode([]rune(a)), utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
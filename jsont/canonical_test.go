@@ -0,0 +1,77 @@
+package jsont
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestMarshalCanonical(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		value    interface{}
+		expected string
+		err      error
+	}{
+		{
+			name:     "sorts keys and drops whitespace",
+			value:    Object{"b": []interface{}{true, nil}, "a": map[string]interface{}{"d": "x", "c": 1}},
+			expected: `{"a":{"c":1,"d":"x"},"b":[true,null]}`,
+		},
+		{
+			name: "sorts keys by UTF-16 code units",
+			value: Object{
+				"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\U0001F600": 5, "\u0080": 6, "\u00f6": 7,
+			},
+			expected: "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"\u00f6\":7,\"\u20ac\":1,\"\U0001F600\":5,\"\ufb33\":3}",
+		},
+		{
+			name: "formats numbers like ECMAScript",
+			value: []interface{}{
+				0.0, math.Copysign(0, -1), 1, -1.5, 4.50, 2e-3, 0.000001, 1e-7,
+				1e21, 1e30, 123456789012345680000.0, 333333333.33333329, 9007199254740993,
+			},
+			expected: "[0,0,1,-1.5,4.5,0.002,0.000001,1e-7,1e+21,1e+30,123456789012345680000,333333333.3333333,9007199254740992]",
+		},
+		{
+			name:     "only escapes what JSON requires",
+			value:    "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"/<>&",
+			expected: "\"\u20ac$\\u000f\\nA'B\\\"\\\\\\\\\\\"/<>&\"",
+		},
+		{
+			name: "encodes values that aren't decoded JSON",
+			value: Object{"user": struct {
+				Name string `json:"name"`
+				Age  int    `json:"age"`
+			}{"bob", 42}},
+			expected: `{"user":{"age":42,"name":"bob"}}`,
+		},
+		{
+			name:  "rejects NaN",
+			value: Object{"n": math.NaN()},
+			err:   errors.New("NaN has no JSON representation"),
+		},
+		{
+			name:  "rejects invalid UTF-8",
+			value: "\xff",
+			err:   errors.New(`"\xff" is not valid UTF-8`),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			b, err := MarshalCanonical(tc.value)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if string(b) != tc.expected {
+				t.Errorf("expected: %s\ngot: %s", tc.expected, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package config loads YAML and TOML documents as jsont.Objects, so
+// config files can be checked with the same getters and assertions as
+// JSON payloads.
+package config
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dedwardstech/test/internal/path"
+	"github.com/dedwardstech/test/jsont"
+)
+
+// UnmarshalYAML unmarshals a YAML document and normalizes it to the types
+// jsont.Unmarshal produces: string keys, float64 numbers, []interface{} arrays.
+// Scalar keys, like 1 or true, become strings and timestamps become
+// RFC 3339 strings. Anything with no JSON equivalent, like a key that's a
+// list, is an error. An empty document is an empty Object.
+func UnmarshalYAML(payload []byte) (jsont.Object, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(payload, &v); err != nil {
+		return nil, errors.Wrap(err, "failed to parse YAML")
+	}
+
+	return configObject(v)
+}
+
+// UnmarshalTOML unmarshals a TOML document and normalizes it the same way
+// as UnmarshalYAML. Local dates and times keep their TOML formatting, as
+// they have no zone to put in an RFC 3339 string.
+func UnmarshalTOML(payload []byte) (jsont.Object, error) {
+	var m map[string]interface{}
+	if err := toml.Unmarshal(payload, &m); err != nil {
+		return nil, errors.Wrap(err, "failed to parse TOML")
+	}
+
+	return configObject(m)
+}
+
+func configObject(v interface{}) (jsont.Object, error) {
+	if v == nil {
+		return jsont.Object{}, nil
+	}
+
+	normalized, err := jsonValue("", v)
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := normalized.(map[string]interface{})
+	if !ok {
+		return nil, jsont.ErrNotAnObject
+	}
+
+	return jsont.Set(m), nil
+}
+
+// jsonValue converts a decoded YAML or TOML value at path p to its JSON
+// equivalent
+func jsonValue(p string, v interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case nil, string, bool:
+		return t, nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	case float64:
+		if math.IsNaN(t) || math.IsInf(t, 0) {
+			return nil, errors.Errorf("%s: %v has no JSON equivalent", describePath(p), t)
+		}
+
+		return t, nil
+	case time.Time:
+		return formatTime(t), nil
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			converted, err := jsonValue(path.Key(p, k), val)
+			if err != nil {
+				return nil, err
+			}
+
+			m[k] = converted
+		}
+
+		return m, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			key, err := jsonKey(p, k)
+			if err != nil {
+				return nil, err
+			}
+
+			converted, err := jsonValue(path.Key(p, key), val)
+			if err != nil {
+				return nil, err
+			}
+
+			m[key] = converted
+		}
+
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(t))
+		for i, val := range t {
+			converted, err := jsonValue(path.Index(p, i), val)
+			if err != nil {
+				return nil, err
+			}
+
+			a[i] = converted
+		}
+
+		return a, nil
+	case []map[string]interface{}:
+		a := make([]interface{}, len(t))
+		for i, val := range t {
+			converted, err := jsonValue(path.Index(p, i), val)
+			if err != nil {
+				return nil, err
+			}
+
+			a[i] = converted
+		}
+
+		return a, nil
+	}
+
+	return nil, errors.Errorf("%s: %T has no JSON equivalent", describePath(p), v)
+}
+
+// jsonKey converts a scalar map key to a string, the way it's written
+func jsonKey(p string, k interface{}) (string, error) {
+	switch t := k.(type) {
+	case string:
+		return t, nil
+	case int, int64, uint64, float64, bool:
+		return fmt.Sprint(t), nil
+	case time.Time:
+		return formatTime(t), nil
+	case nil:
+		return "null", nil
+	}
+
+	return "", errors.Errorf("%s: key %v is a %T; object keys must be scalars", describePath(p), k, k)
+}
+
+// formatTime formats a timestamp as RFC 3339, or the way TOML writes it
+// for the local date and time types
+func formatTime(t time.Time) string {
+	switch t.Location().String() {
+	case "datetime-local":
+		return t.Format("2006-01-02T15:04:05.999999999")
+	case "date-local":
+		return t.Format("2006-01-02")
+	case "time-local":
+		return t.Format("15:04:05.999999999")
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+func describePath(p string) string {
+	if p == "" {
+		return "(root)"
+	}
+
+	return p
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+	"github.com/dedwardstech/test/jsont"
+)
+
+func TestUnmarshalYAML(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		payload  string
+		expected jsont.Object
+		err      error
+	}{
+		{
+			name: "normalizes to JSON types",
+			payload: `
+name: api
+port: 8080
+ratio: 0.5
+debug: false
+tags: [a, b]
+owner: ~
+released: 2021-01-30T12:00:00Z
+limits:
+  1: low
+  true: yes
+  2021-01-30: high
+servers:
+  - host: a
+    weight: 2
+`,
+			expected: jsont.Object{
+				"name":     "api",
+				"port":     float64(8080),
+				"ratio":    0.5,
+				"debug":    false,
+				"tags":     []interface{}{"a", "b"},
+				"owner":    nil,
+				"released": "2021-01-30T12:00:00Z",
+				"limits":   map[string]interface{}{"1": "low", "true": "yes", "2021-01-30T00:00:00Z": "high"},
+				"servers":  []interface{}{map[string]interface{}{"host": "a", "weight": float64(2)}},
+			},
+		},
+		{
+			name:     "treats an empty document as an empty object",
+			payload:  "",
+			expected: jsont.Object{},
+		},
+		{
+			name:    "rejects values with no JSON equivalent",
+			payload: "limits:\n  max: .inf\n",
+			err:     errors.New("limits.max: +Inf has no JSON equivalent"),
+		},
+		{
+			name:    "rejects keys that aren't scalars",
+			payload: "matrix:\n  ? [a, b]\n  : x\n",
+			err:     errors.New("failed to parse YAML: yaml: invalid map key: []interface {}{\"a\", \"b\"}"),
+		},
+		{
+			name:    "rejects documents that aren't objects",
+			payload: "- a\n- b\n",
+			err:     jsont.ErrNotAnObject,
+		},
+		{
+			name:    "returns parse errors",
+			payload: "a: [b",
+			err:     errors.New("failed to parse YAML: yaml: line 1: did not find expected ',' or ']'"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj, err := UnmarshalYAML([]byte(tc.payload))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, obj)
+			}
+		})
+	}
+}
+
+func TestUnmarshalTOML(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		payload  string
+		expected jsont.Object
+		err      error
+	}{
+		{
+			name: "normalizes to JSON types",
+			payload: `
+name = "api"
+port = 8080
+ratio = 0.5
+released = 2021-01-30T12:00:00Z
+birthday = 2021-01-30
+
+[limits]
+max = 10
+
+[[servers]]
+host = "a"
+
+[[servers]]
+host = "b"
+`,
+			expected: jsont.Object{
+				"name":     "api",
+				"port":     float64(8080),
+				"ratio":    0.5,
+				"released": "2021-01-30T12:00:00Z",
+				"birthday": "2021-01-30",
+				"limits":   map[string]interface{}{"max": float64(10)},
+				"servers": []interface{}{
+					map[string]interface{}{"host": "a"},
+					map[string]interface{}{"host": "b"},
+				},
+			},
+		},
+		{
+			name:    "rejects values with no JSON equivalent",
+			payload: "ratios = [1.0, nan]",
+			err:     errors.New("ratios[1]: NaN has no JSON equivalent"),
+		},
+		{
+			name:    "returns parse errors",
+			payload: "name = ",
+			err:     errors.New("failed to parse TOML: toml: line 0 (last key \"name\"): unexpected EOF; expected value"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj, err := UnmarshalTOML([]byte(tc.payload))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, obj)
+			}
+		})
+	}
+}
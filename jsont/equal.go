@@ -0,0 +1,162 @@
+package jsont
+
+import (
+	"reflect"
+
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// EquateNumbers makes numbers equal when their values are, whatever their
+// types, so int 1, float64 1 and json.Number "1" are all equal. Contains
+// and Match always compare numbers this way.
+func EquateNumbers() MatchOption {
+	return func(c *matchConfig) {
+		c.strictNumbers = false
+	}
+}
+
+// NullEqualsMissing treats a key whose value is null the same as a key
+// that isn't there at all
+func NullEqualsMissing() MatchOption {
+	return func(c *matchConfig) {
+		c.nullEqualsMissing = true
+	}
+}
+
+// IgnoreExtraKeysInA allows objects in a, or the actual Object, to have
+// keys b doesn't
+func IgnoreExtraKeysInA() MatchOption {
+	return func(c *matchConfig) {
+		c.extraKeysInA = true
+	}
+}
+
+// IgnoreExtraKeysInB allows objects in b, or the expected Object, to have
+// keys a doesn't
+func IgnoreExtraKeysInB() MatchOption {
+	return func(c *matchConfig) {
+		c.extraKeysInB = true
+	}
+}
+
+// UnorderedArrays compares arrays without regard to order. With no
+// patterns every array is unordered; otherwise only the arrays at
+// patterns, which use the same wildcards as a Normalizer, like
+// "users[*].roles".
+func UnorderedArrays(patterns ...string) MatchOption {
+	return func(c *matchConfig) {
+		if len(patterns) == 0 {
+			c.arrays = ArraysUnordered
+			return
+		}
+
+		for _, p := range patterns {
+			c.unorderedAt = append(c.unorderedAt, compilePattern(p))
+		}
+	}
+}
+
+// TrimStrings ignores leading and trailing whitespace when comparing
+// strings
+func TrimStrings() MatchOption {
+	return func(c *matchConfig) {
+		c.trimStrings = true
+	}
+}
+
+// Equal compares two Objects and reports every difference between them,
+// the way Match does, with b as the wanted value. Unlike Match, numbers
+// must have the same type to be equal unless EquateNumbers is given.
+//
+// Slices and maps with string keys of any type are compared as arrays
+// and objects, so an Object built in Go can be compared with one decoded
+// from a payload:
+//
+//	err := jsont.Equal(decoded, jsont.Object{"ids": []int{1, 2}},
+//		jsont.EquateNumbers(), jsont.UnorderedArrays("ids"))
+func Equal(a, b Object, opts ...MatchOption) error {
+	m := newMatcher(append([]MatchOption{strictNumbers}, opts...))
+
+	mismatches := m.match("", generic(map[string]interface{}(b)), generic(map[string]interface{}(a)), false)
+	if len(mismatches) > 0 {
+		return MismatchError(mismatches)
+	}
+
+	return nil
+}
+
+// strictNumbers is Equal's default, which EquateNumbers undoes
+func strictNumbers(c *matchConfig) {
+	c.strictNumbers = true
+}
+
+// unordered reports whether the array at p was made unordered by
+// UnorderedArrays
+func (m *matcher) unordered(p string) bool {
+	if len(m.cfg.unorderedAt) == 0 {
+		return false
+	}
+
+	segments := splitPath(p)
+	if p == "" {
+		segments = nil
+	}
+
+	for _, pattern := range m.cfg.unorderedAt {
+		if matchPattern(pattern, segments) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generic converts slices and string-keyed maps of any type to
+// []interface{} and map[string]interface{}, all the way down. Other
+// values are left as they are.
+func generic(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+
+	if _, ok := v.(Matcher); ok {
+		return v
+	}
+
+	if _, ok := number.Float64(v); ok {
+		return v
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = generic(rv.Index(i).Interface())
+		}
+
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v
+		}
+
+		if rv.IsNil() {
+			return nil
+		}
+
+		out := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			out[iter.Key().String()] = generic(iter.Value().Interface())
+		}
+
+		return out
+	}
+
+	return v
+}
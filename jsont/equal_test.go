@@ -0,0 +1,102 @@
+package jsont
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestEqual(tt *testing.T) {
+	decoded, err := Unmarshal([]byte(`{
+		"id": 7,
+		"name": " bob ",
+		"roles": ["dev", "admin"],
+		"teams": [{"id": 1, "tags": ["b", "a"]}],
+		"manager": null
+	}`))
+	if err != nil {
+		tt.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	built := Object{
+		"id":    int64(7),
+		"name":  "bob",
+		"roles": []string{"admin", "dev"},
+		"teams": []map[string]interface{}{{"id": json.Number("1"), "tags": []string{"a", "b"}}},
+	}
+
+	testcases := []struct {
+		name string
+		a, b Object
+		opts []MatchOption
+		err  error
+	}{
+		{
+			name: "reports every difference by default",
+			a:    decoded,
+			b:    built,
+			err: errors.New(`id: wanted int64 7; got float64 7
+name: wanted "bob"; got " bob "
+roles[0]: wanted "admin"; got "dev"
+roles[1]: wanted "dev"; got "admin"
+teams[0].id: wanted json.Number 1; got float64 1
+teams[0].tags[0]: wanted "a"; got "b"
+teams[0].tags[1]: wanted "b"; got "a"
+manager: unexpected key`),
+		},
+		{
+			name: "passes with the options relaxed",
+			a:    decoded,
+			b:    built,
+			opts: []MatchOption{EquateNumbers(), NullEqualsMissing(), TrimStrings(), UnorderedArrays()},
+			err:  nil,
+		},
+		{
+			name: "only treats the arrays at patterns as unordered",
+			a:    decoded,
+			b:    built,
+			opts: []MatchOption{EquateNumbers(), NullEqualsMissing(), TrimStrings(), UnorderedArrays("teams[*].tags")},
+			err: errors.New(`roles[0]: wanted "admin"; got "dev"
+roles[1]: wanted "dev"; got "admin"`),
+		},
+		{
+			name: "treats the arrays at quoted keys as unordered",
+			a:    Object{"a.b": []interface{}{1.0, 2.0}},
+			b:    Object{"a.b": []interface{}{2.0, 1.0}},
+			opts: []MatchOption{UnorderedArrays(`["a.b"]`)},
+			err:  nil,
+		},
+		{
+			name: "ignores extra keys in a",
+			a:    Object{"id": 1.0, "extra": true},
+			b:    Object{"id": 1.0},
+			opts: []MatchOption{IgnoreExtraKeysInA()},
+			err:  nil,
+		},
+		{
+			name: "ignores extra keys in b",
+			a:    Object{"id": 1.0},
+			b:    Object{"id": 1.0, "extra": true},
+			opts: []MatchOption{IgnoreExtraKeysInB()},
+			err:  nil,
+		},
+		{
+			name: "still reports extra keys on the other side",
+			a:    Object{"id": 1.0},
+			b:    Object{"id": 1.0, "extra": true},
+			opts: []MatchOption{IgnoreExtraKeysInA()},
+			err:  errors.New("extra: json path does not exist"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := Equal(tc.a, tc.b, tc.opts...)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
@@ -8,13 +8,15 @@ import (
 )
 
 var (
-	// ErrPathIndexFailed indicates the path given points to a value that isn't a map.
+	// ErrPathIndexFailed indicates the path given goes through a value it can't
+	// go into: a key into a value that isn't an object, or an index into a
+	// value that isn't an array.
 	//
 	// Given a path "my.cool.path", where "my.cool" is filled by a primitive value.
-	// The path exists, but you can't go any further because my.cool isn't a map.
+	// The path exists, but you can't go any further because my.cool isn't an object.
 	// Where as ErrPropertyDoesNotExist indicates that the path simply doesn't
 	// exist in the payload.
-	ErrPathIndexFailed = errors.New("cannot index into non-map type")
+	ErrPathIndexFailed = errors.New("path goes into a value that isn't an object or array")
 
 	// ErrPropertyDoesNotExist indicates that the path given does not exist in the JSON
 	ErrPropertyDoesNotExist = errors.New("json path does not exist")
@@ -37,7 +39,21 @@ func (e TypeCastError) Error() string {
 // NewTypeCastError creates a TypeCastError given two different types
 func NewTypeCastError(wanted, got reflect.Type) TypeCastError {
 	return TypeCastError{
-		wantedType: wanted.Name(),
-		gotType:    got.Name(),
+		wantedType: typeName(wanted),
+		gotType:    typeName(got),
 	}
 }
+
+// typeName names a type for error messages. JSON nulls have no type at all,
+// and unnamed types like []interface{} have an empty Name.
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "null"
+	}
+
+	if t.Name() == "" {
+		return t.String()
+	}
+
+	return t.Name()
+}
@@ -0,0 +1,250 @@
+package jsont
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// TestingT is the part of testing.TB the assertion helpers need. It's
+// an interface so the helpers can be tested without failing real tests.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+// Expectation chains assertions against the value at a property path.
+// Failed assertions don't stop the chain; they're collected and reported
+// together, with their paths, when the test finishes.
+//
+//	jsont.Expect(t, body).
+//	  Path("user.name").IsString().Equals("bob").
+//	  Path("user.roles").Len(3).Contains("admin")
+type Expectation struct {
+	obj    Object
+	path   string
+	failed bool
+	report *report
+}
+
+// report collects the failures of every Expectation in a chain.
+type report struct {
+	failures []string
+	paths    []string
+}
+
+func (r *report) err() error {
+	if len(r.failures) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(r.failures, "\n"))
+}
+
+// Expect starts a chain of assertions against obj. Any failures are
+// reported to t in a single message once the test has finished, followed
+// by obj rendered with the failing paths highlighted.
+func Expect(t TestingT, obj Object) *Expectation {
+	t.Helper()
+
+	r := &report{}
+	t.Cleanup(func() {
+		t.Helper()
+
+		if err := r.err(); err != nil {
+			t.Errorf("failed expectations:\n%v\n\n%s", err, obj.Render(Highlight(r.paths...)))
+		}
+	})
+
+	return &Expectation{obj: obj, report: r}
+}
+
+// Path moves the chain to a new property path. Later assertions are made
+// against the value found there.
+func (e *Expectation) Path(propertyPath string) *Expectation {
+	next := &Expectation{
+		obj:    e.obj,
+		path:   propertyPath,
+		report: e.report,
+	}
+
+	if _, err := e.obj.Get(propertyPath); err != nil {
+		next.fail(err)
+	}
+
+	return next
+}
+
+// Err returns every failure collected by the chain so far, or nil.
+func (e *Expectation) Err() error {
+	return e.report.err()
+}
+
+// IsString asserts the current value is a string
+func (e *Expectation) IsString() *Expectation {
+	return e.check(func() error {
+		_, err := e.obj.GetStr(e.path)
+		return err
+	})
+}
+
+// IsNumber asserts the current value is a number
+func (e *Expectation) IsNumber() *Expectation {
+	return e.check(func() error {
+		_, err := e.obj.GetNumber(e.path)
+		return err
+	})
+}
+
+// IsBool asserts the current value is a bool
+func (e *Expectation) IsBool() *Expectation {
+	return e.check(func() error {
+		_, err := e.obj.GetBool(e.path)
+		return err
+	})
+}
+
+// IsSlice asserts the current value is an array
+func (e *Expectation) IsSlice() *Expectation {
+	return e.check(func() error {
+		_, err := e.obj.GetSlice(e.path)
+		return err
+	})
+}
+
+// IsObject asserts the current value is an object
+func (e *Expectation) IsObject() *Expectation {
+	return e.check(func() error {
+		_, err := e.obj.GetObj(e.path)
+		return err
+	})
+}
+
+// IsNull asserts the current value is null
+func (e *Expectation) IsNull() *Expectation {
+	return e.check(func() error {
+		val, _ := e.obj.Get(e.path)
+		if val != nil {
+			return NewTypeCastError(nil, reflect.TypeOf(val))
+		}
+
+		return nil
+	})
+}
+
+// Equals asserts the current value equals expected. Numbers are compared
+// by value, so Equals(3) passes against the float64 3 from a payload, and
+// expected may be a Matcher.
+//
+// Every difference is reported at its own path, so a nested value that
+// doesn't match says where it diverged.
+func (e *Expectation) Equals(expected interface{}) *Expectation {
+	if e.failed {
+		return e
+	}
+
+	val, _ := e.obj.Get(e.path)
+	for _, m := range newMatcher(nil).match(e.path, expected, val, false) {
+		e.failAt(m.Path, m.Reason)
+	}
+
+	return e
+}
+
+// Len asserts the length of the current value. Arrays count elements,
+// objects count keys and strings count characters.
+func (e *Expectation) Len(n int) *Expectation {
+	return e.check(func() error {
+		val, _ := e.obj.Get(e.path)
+
+		var l int
+		if m, ok := asMap(val); ok {
+			l = len(m)
+		} else {
+			switch v := val.(type) {
+			case []interface{}:
+				l = len(v)
+			case string:
+				l = utf8.RuneCountInString(v)
+			default:
+				return fmt.Errorf("cannot take the length of %s", typeName(reflect.TypeOf(val)))
+			}
+		}
+
+		if l != n {
+			return fmt.Errorf("wanted length %d; got %d", n, l)
+		}
+
+		return nil
+	})
+}
+
+// Contains asserts the current value contains item. Arrays must hold an
+// element equal to, or matched by, item, strings must contain item as a substring and
+// objects must have item as a key.
+func (e *Expectation) Contains(item interface{}) *Expectation {
+	return e.check(func() error {
+		val, _ := e.obj.Get(e.path)
+
+		if m, ok := asMap(val); ok {
+			key, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("object keys are strings; got %v", item)
+			}
+
+			if _, ok := m[key]; !ok {
+				return fmt.Errorf("wanted key %q in object", key)
+			}
+
+			return nil
+		}
+
+		switch v := val.(type) {
+		case []interface{}:
+			for _, elem := range v {
+				if matches(item, elem) {
+					return nil
+				}
+			}
+
+			return fmt.Errorf("wanted %v in %v", item, v)
+		case string:
+			sub, ok := item.(string)
+			if !ok || !strings.Contains(v, sub) {
+				return fmt.Errorf("wanted %q to contain %v", v, item)
+			}
+
+			return nil
+		}
+
+		return fmt.Errorf("cannot look for values in %s", typeName(reflect.TypeOf(val)))
+	})
+}
+
+// check runs an assertion unless an earlier one on the same path has
+// already failed. One broken path only gets reported once.
+func (e *Expectation) check(assertion func() error) *Expectation {
+	if e.failed {
+		return e
+	}
+
+	if err := assertion(); err != nil {
+		e.fail(err)
+	}
+
+	return e
+}
+
+func (e *Expectation) fail(err error) {
+	e.failAt(e.path, err.Error())
+}
+
+func (e *Expectation) failAt(path, reason string) {
+	e.failed = true
+	e.report.failures = append(e.report.failures, fmt.Sprintf("%s: %s", path, reason))
+	e.report.paths = append(e.report.paths, path)
+}
@@ -0,0 +1,119 @@
+package jsont
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestExpect(tt *testing.T) {
+	body := Object{
+		"user": map[string]interface{}{
+			"name":   "bob",
+			"age":    float64(42),
+			"admin":  true,
+			"roles":  []interface{}{"admin", "dev", "ops"},
+			"team":   map[string]interface{}{"id": "t1"},
+			"banned": nil,
+		},
+	}
+
+	testcases := []struct {
+		name   string
+		expect func(e *Expectation)
+		err    error
+	}{
+		{
+			name: "passes when every assertion holds",
+			expect: func(e *Expectation) {
+				e.Path("user.name").IsString().Equals("bob").Len(3).Contains("ob").
+					Path("user.age").IsNumber().Equals(42).
+					Path("user.admin").IsBool().Equals(true).
+					Path("user.roles").IsSlice().Len(3).Contains("admin").
+					Path("user.team").IsObject().Contains("id").Len(1).
+					Path("user.banned").IsNull()
+			},
+			err: nil,
+		},
+		{
+			name: "reports type mismatches with their path",
+			expect: func(e *Expectation) {
+				e.Path("user.age").IsString()
+			},
+			err: errors.New("user.age: attempted to type float64 as string"),
+		},
+		{
+			name: "reports missing paths",
+			expect: func(e *Expectation) {
+				e.Path("user.email").IsString()
+			},
+			err: errors.New("user.email: json path does not exist"),
+		},
+		{
+			name: "only reports the first failure on a path",
+			expect: func(e *Expectation) {
+				e.Path("user.name").Equals("alice").Len(10)
+			},
+			err: errors.New(`user.name: wanted "alice"; got "bob"`),
+		},
+		{
+			name: "reports every nested difference at its path",
+			expect: func(e *Expectation) {
+				e.Path("user").Equals(map[string]interface{}{
+					"name":   "alice",
+					"age":    42,
+					"admin":  false,
+					"roles":  []interface{}{"admin", "dev", "ops"},
+					"team":   map[string]interface{}{"id": "t2"},
+					"banned": nil,
+				})
+			},
+			err: errors.New(`user.admin: wanted false; got true
+user.name: wanted "alice"; got "bob"
+user.team.id: wanted "t2"; got "t1"`),
+		},
+		{
+			name: "collects failures across paths",
+			expect: func(e *Expectation) {
+				e.Path("user.roles").Len(2).Contains("root").
+					Path("user.banned").IsBool()
+			},
+			err: errors.New("user.roles: wanted length 2; got 3\nuser.banned: attempted to type null as bool"),
+		},
+		{
+			name: "reports values missing from arrays",
+			expect: func(e *Expectation) {
+				e.Path("user.roles").Contains("root")
+			},
+			err: errors.New("user.roles: wanted root in [admin dev ops]"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			ft := &fakeT{}
+			e := Expect(ft, body)
+			tc.expect(e)
+
+			if testErr := compare.Errors(tc.err, e.Err()); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			ft.finish()
+			if tc.err == nil && len(ft.errs) > 0 {
+				t.Errorf("expected no reported failures, got %v", ft.errs)
+			}
+
+			if tc.err != nil && len(ft.errs) != 1 {
+				t.Errorf("expected failures to be reported once, got %d reports", len(ft.errs))
+			}
+
+			if tc.err != nil && len(ft.errs) == 1 && !strings.Contains(ft.errs[0], "\n\n  {\n") {
+				t.Errorf("expected the report to render the payload, got %s", ft.errs[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package jsont
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/internal/path"
+)
+
+// Flatten returns every leaf value of the Object keyed by its full property
+// path, like "users[0].name", in the grammar Get accepts. Empty objects and
+// arrays are leaves too, so Unflatten can rebuild them, and keys holding
+// "." or "[" are quoted, like `labels["app.name"]`.
+func (o Object) Flatten() map[string]interface{} {
+	flat := make(map[string]interface{})
+	for k, v := range o {
+		flatten(flat, path.Key("", k), v)
+	}
+
+	return flat
+}
+
+func flatten(flat map[string]interface{}, p string, v interface{}) {
+	if m, ok := asMap(v); ok && len(m) > 0 {
+		for k, val := range m {
+			flatten(flat, path.Key(p, k), val)
+		}
+
+		return
+	}
+
+	if a, ok := v.([]interface{}); ok && len(a) > 0 {
+		for i, val := range a {
+			flatten(flat, path.Index(p, i), val)
+		}
+
+		return
+	}
+
+	flat[p] = v
+}
+
+// Unflatten rebuilds an Object from values keyed by property path, the
+// reverse of Flatten. Array elements no path sets are null. Paths that
+// conflict, like "a" and "a.b", are an error, even when "a" is null.
+func Unflatten(flat map[string]interface{}) (Object, error) {
+	paths := make([]string, 0, len(flat))
+	for p := range flat {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var root interface{} = map[string]interface{}{}
+	for _, p := range paths {
+		if p == "" {
+			return nil, errors.New("cannot unflatten an empty property path")
+		}
+
+		var err error
+		if root, err = unflatten(root, splitPath(p), flat[p]); err != nil {
+			return nil, errors.Wrapf(err, "%s", p)
+		}
+	}
+
+	return Set(fillHoles(root).(map[string]interface{})), nil
+}
+
+// maxIndexGap is how far past the end of an array Unflatten will set an
+// element. The elements in between are filled with null, so a typo like
+// "a[3000000000]" would otherwise allocate until the process runs out of
+// memory.
+const maxIndexGap = 10000
+
+// hole marks a value no path has set yet. It's told apart from nil so a
+// null leaf conflicts with paths under it, like any other leaf.
+type hole struct{}
+
+// unflatten sets v at the path segments under container, creating objects
+// and arrays on the way, and returns the container, which is new if it
+// had to be created or grown.
+func unflatten(container interface{}, segments Path, v interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		if container != (hole{}) {
+			return nil, errors.New("conflicts with another path")
+		}
+
+		return v, nil
+	}
+
+	s := segments[0]
+	if s.IsIndex {
+		if container == (hole{}) {
+			container = []interface{}{}
+		}
+
+		a, ok := container.([]interface{})
+		if !ok {
+			return nil, errors.New("conflicts with another path")
+		}
+
+		if s.Index-len(a) > maxIndexGap {
+			return nil, errors.Errorf("index %d is more than %d past the end of the array", s.Index, maxIndexGap)
+		}
+
+		for len(a) <= s.Index {
+			a = append(a, hole{})
+		}
+
+		val, err := unflatten(a[s.Index], segments[1:], v)
+		if err != nil {
+			return nil, err
+		}
+
+		a[s.Index] = val
+		return a, nil
+	}
+
+	if container == (hole{}) {
+		container = map[string]interface{}{}
+	}
+
+	m, ok := container.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("conflicts with another path")
+	}
+
+	existing, ok := m[s.Key]
+	if !ok {
+		existing = hole{}
+	}
+
+	val, err := unflatten(existing, segments[1:], v)
+	if err != nil {
+		return nil, err
+	}
+
+	m[s.Key] = val
+	return m, nil
+}
+
+// fillHoles replaces the array elements no path set with null
+func fillHoles(v interface{}) interface{} {
+	switch t := v.(type) {
+	case hole:
+		return nil
+	case map[string]interface{}:
+		for k, val := range t {
+			t[k] = fillHoles(val)
+		}
+	case []interface{}:
+		for i, val := range t {
+			t[i] = fillHoles(val)
+		}
+	}
+
+	return v
+}
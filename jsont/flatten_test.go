@@ -0,0 +1,121 @@
+package jsont
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestObject_Flatten(t *testing.T) {
+	obj := Object{
+		"id": "u1",
+		"user": map[string]interface{}{
+			"name":  "bob",
+			"roles": []interface{}{"admin", map[string]interface{}{"team": "ops"}},
+			"tags":  []interface{}{},
+			"prefs": map[string]interface{}{},
+		},
+		"manager": nil,
+		"labels":  map[string]interface{}{"app.name": "api", "tier[0]": "web"},
+		"":        map[string]interface{}{"": "blank"},
+	}
+
+	expected := map[string]interface{}{
+		"id":                 "u1",
+		"user.name":          "bob",
+		"user.roles[0]":      "admin",
+		"user.roles[1].team": "ops",
+		"user.tags":          []interface{}{},
+		"user.prefs":         map[string]interface{}{},
+		"manager":            nil,
+		`labels["app.name"]`: "api",
+		`labels["tier[0]"]`:  "web",
+		`[""][""]`:           "blank",
+	}
+
+	flat := obj.Flatten()
+	if !reflect.DeepEqual(flat, expected) {
+		t.Fatalf("expected: %v\ngot: %v", expected, flat)
+	}
+
+	for p, v := range flat {
+		if got, err := obj.Get(p); err != nil || !reflect.DeepEqual(got, v) {
+			t.Errorf("expected Get(%q) to return %v, got %v (%v)", p, v, got, err)
+		}
+	}
+
+	back, err := Unflatten(flat)
+	if err != nil {
+		t.Fatalf("failed to unflatten: %v", err)
+	}
+
+	if !reflect.DeepEqual(back, obj) {
+		t.Errorf("expected the round trip to return %v, got %v", obj, back)
+	}
+}
+
+func TestUnflatten(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		flat     map[string]interface{}
+		expected Object
+		err      error
+	}{
+		{
+			name: "fills array elements no path sets with null",
+			flat: map[string]interface{}{"a[2].b": 1.0, "a[0]": "x"},
+			expected: Object{
+				"a": []interface{}{"x", nil, map[string]interface{}{"b": 1.0}},
+			},
+		},
+		{
+			name: "rejects paths that conflict",
+			flat: map[string]interface{}{"a": 1.0, "a.b": 2.0},
+			err:  errors.New("a.b: conflicts with another path"),
+		},
+		{
+			name: "rejects paths under a null leaf",
+			flat: map[string]interface{}{"a": nil, "a.b": 2.0},
+			err:  errors.New("a.b: conflicts with another path"),
+		},
+		{
+			name: "rejects paths under a null array element",
+			flat: map[string]interface{}{"a[0]": nil, "a[0].b": 2.0},
+			err:  errors.New("a[0].b: conflicts with another path"),
+		},
+		{
+			name: "keeps null leaves",
+			flat: map[string]interface{}{"a[1]": nil, "b": nil},
+			expected: Object{
+				"a": []interface{}{nil, nil},
+				"b": nil,
+			},
+		},
+		{
+			name: "rejects keys used as arrays and objects",
+			flat: map[string]interface{}{"a[0]": 1.0, "a.b": 2.0},
+			err:  errors.New("a[0]: conflicts with another path"),
+		},
+		{
+			name: "rejects indexes far past the end of an array",
+			flat: map[string]interface{}{"a[3000000000000]": 1.0},
+			err:  errors.New("a[3000000000000]: index 3000000000000 is more than 10000 past the end of the array"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj, err := Unflatten(tc.flat)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, obj)
+			}
+		})
+	}
+}
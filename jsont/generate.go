@@ -0,0 +1,231 @@
+package jsont
+
+import (
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// initialisms are written in upper case in Go identifiers, per Go style
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true,
+	"UI": true, "URI": true, "URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// GenerateStructs writes Go type declarations that the samples can be
+// decoded into. The top level type is called name, and every nested
+// object gets a named type of its own. Fields get json tags; keys that are
+// missing from some samples, or null in some, become pointers with
+// omitempty.
+//
+// The output is gofmt'd declarations without a package clause, ready to
+// paste into a file or to follow one.
+func GenerateStructs(name string, samples ...Object) ([]byte, error) {
+	s := newShape()
+	for _, sample := range samples {
+		s.add(map[string]interface{}(sample))
+	}
+
+	g := &generator{used: make(map[string]bool)}
+	root := g.reserve(exportedName(name))
+	g.queue = append(g.queue, namedShape{name: root, shape: s})
+
+	var buf strings.Builder
+	for i := 0; i < len(g.queue); i++ {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		g.writeStruct(&buf, g.queue[i])
+	}
+
+	src, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid Go source: %v", err)
+	}
+
+	return src, nil
+}
+
+type namedShape struct {
+	name  string
+	shape *shape
+}
+
+type generator struct {
+	used  map[string]bool
+	queue []namedShape
+}
+
+// reserve claims a type name, numbering it if it's been taken already
+func (g *generator) reserve(name string) string {
+	unique := name
+	for i := 2; g.used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.used[unique] = true
+
+	return unique
+}
+
+func (g *generator) writeStruct(buf *strings.Builder, ns namedShape) {
+	keys := make([]string, 0, len(ns.shape.props))
+	for k := range ns.shape.props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(buf, "type %s struct {\n", ns.name)
+
+	fields := make(map[string]bool)
+	for _, key := range keys {
+		prop := ns.shape.props[key]
+		optional := !ns.shape.required(key) || prop.types["null"]
+
+		field := exportedName(key)
+		for i := 2; fields[field]; i++ {
+			field = fmt.Sprintf("%s%d", exportedName(key), i)
+		}
+		fields[field] = true
+
+		typ := g.goType(ns.name, field, prop, optional)
+
+		tag := key
+		if optional {
+			tag += ",omitempty"
+		}
+
+		fmt.Fprintf(buf, "%s %s `json:%q`\n", field, typ, tag)
+	}
+
+	buf.WriteString("}\n")
+}
+
+// goType picks the Go type for a shape, queueing a new struct type for
+// objects. Optional scalars and structs become pointers; slices and
+// interface{} can already be nil.
+func (g *generator) goType(parent, field string, s *shape, optional bool) string {
+	var types []string
+	for _, t := range s.typeNames() {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+
+	if len(types) != 1 {
+		return "interface{}"
+	}
+
+	var typ string
+	switch types[0] {
+	case "boolean":
+		typ = "bool"
+	case "integer":
+		typ = "int64"
+	case "number":
+		typ = "float64"
+	case "string":
+		typ = "string"
+	case "object":
+		typ = g.nestedType(parent, field, s)
+	case "array":
+		if s.items == nil || s.items.seen == 0 {
+			return "[]interface{}"
+		}
+
+		return "[]" + g.goType(parent, singular(field), s.items, false)
+	}
+
+	if optional {
+		return "*" + typ
+	}
+
+	return typ
+}
+
+// nestedType names the struct type for an object field. Fields are named
+// after themselves when they can be, and after their parent when that name
+// is already taken.
+func (g *generator) nestedType(parent, field string, s *shape) string {
+	name := field
+	if g.used[name] {
+		name = parent + field
+	}
+
+	name = g.reserve(name)
+	g.queue = append(g.queue, namedShape{name: name, shape: s})
+
+	return name
+}
+
+// exportedName turns a JSON key into an exported Go identifier, splitting
+// on punctuation and lower-to-upper case changes.
+//
+//	"user_id" => UserID
+//	"createdAt" => CreatedAt
+//	"2fa" => X2fa
+func exportedName(key string) string {
+	var words []string
+	var word []rune
+
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = word[:0]
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && unicode.IsLower(runes[i-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		upper := strings.ToUpper(w)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+
+	if unicode.IsDigit([]rune(name)[0]) {
+		return "X" + name
+	}
+
+	return name
+}
+
+// singular names the element type of an array field, like Users => User
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "ss"):
+		return name + "Item"
+	case strings.HasSuffix(name, "s") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+
+	return name + "Item"
+}
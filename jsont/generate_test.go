@@ -0,0 +1,83 @@
+package jsont
+
+import (
+	"testing"
+)
+
+func TestGenerateStructs(tt *testing.T) {
+	testcases := []struct {
+		name, typeName string
+		samples        []string
+		expected       string
+	}{
+		{
+			name:     "maps JSON types to Go types",
+			typeName: "user",
+			samples:  []string{`{"user_id": 1, "name": "bob", "score": 1.5, "admin": true, "tags": ["a"], "extra": [], "createdAt": "x"}`},
+			expected: "type User struct {\n" +
+				"\tAdmin     bool          `json:\"admin\"`\n" +
+				"\tCreatedAt string        `json:\"createdAt\"`\n" +
+				"\tExtra     []interface{} `json:\"extra\"`\n" +
+				"\tName      string        `json:\"name\"`\n" +
+				"\tScore     float64       `json:\"score\"`\n" +
+				"\tTags      []string      `json:\"tags\"`\n" +
+				"\tUserID    int64         `json:\"user_id\"`\n" +
+				"}\n",
+		},
+		{
+			name:     "makes keys missing from some samples optional",
+			typeName: "User",
+			samples:  []string{`{"id": 1, "nick": "b", "meta": {"a": 1}}`, `{"id": 2, "nick": null, "any": 1}`, `{"id": 3, "any": "x"}`},
+			expected: "type User struct {\n" +
+				"\tAny  interface{} `json:\"any,omitempty\"`\n" +
+				"\tID   int64       `json:\"id\"`\n" +
+				"\tMeta *Meta       `json:\"meta,omitempty\"`\n" +
+				"\tNick *string     `json:\"nick,omitempty\"`\n" +
+				"}\n\n" +
+				"type Meta struct {\n" +
+				"\tA int64 `json:\"a\"`\n" +
+				"}\n",
+		},
+		{
+			name:     "names nested types after their fields",
+			typeName: "Order",
+			samples:  []string{`{"items": [{"sku": "x", "order": {"id": 1}}], "customer": {"name": "bob"}}`},
+			expected: "type Order struct {\n" +
+				"\tCustomer Customer `json:\"customer\"`\n" +
+				"\tItems    []Item   `json:\"items\"`\n" +
+				"}\n\n" +
+				"type Customer struct {\n" +
+				"\tName string `json:\"name\"`\n" +
+				"}\n\n" +
+				"type Item struct {\n" +
+				"\tOrder ItemOrder `json:\"order\"`\n" +
+				"\tSku   string    `json:\"sku\"`\n" +
+				"}\n\n" +
+				"type ItemOrder struct {\n" +
+				"\tID int64 `json:\"id\"`\n" +
+				"}\n",
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			samples := make([]Object, len(tc.samples))
+			for i, s := range tc.samples {
+				obj, err := Unmarshal([]byte(s))
+				if err != nil {
+					t.Fatalf("failed to parse sample: %v", err)
+				}
+				samples[i] = obj
+			}
+
+			src, err := GenerateStructs(tc.typeName, samples...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if string(src) != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, src)
+			}
+		})
+	}
+}
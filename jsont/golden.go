@@ -0,0 +1,193 @@
+package jsont
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/dedwardstech/test/diff"
+)
+
+// UpdateEnv is the environment variable that, when set to a true value
+// like 1, makes MatchGolden rewrite its golden files instead of comparing
+// against them. Unlike -update, it works for every package at once:
+//
+//	JSONT_UPDATE=1 go test ./...
+const UpdateEnv = "JSONT_UPDATE"
+
+var registerUpdate sync.Once
+
+// UpdateFlag opts a test package in to the -update flag, which makes
+// MatchGolden rewrite its golden files instead of comparing against them.
+// Call it before the flags are parsed, from init or TestMain:
+//
+//	func init() {
+//		jsont.UpdateFlag()
+//	}
+//
+//	go test ./api -update
+//
+// The flag is registered once, however many times UpdateFlag is called,
+// and not at all if the package already defines its own -update flag.
+// Importing jsont on its own registers nothing.
+func UpdateFlag() {
+	registerUpdate.Do(func() {
+		if flag.Lookup("update") == nil {
+			flag.Bool("update", false, "rewrite golden files with the current payloads")
+		}
+	})
+}
+
+// updateFlag reads the -update flag, if a bool flag by that name has been
+// registered
+func updateFlag() (bool, bool) {
+	f := flag.Lookup("update")
+	if f == nil {
+		return false, false
+	}
+
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false, false
+	}
+
+	b, ok := getter.Get().(bool)
+	return b, ok
+}
+
+// Redacted replaces the values at redacted paths in golden files
+const Redacted = "<redacted>"
+
+// GoldenOption configures MatchGolden
+type GoldenOption func(*goldenConfig)
+
+type goldenConfig struct {
+	dir        string
+	redact     []string
+	normalizer *Normalizer
+	update     bool
+}
+
+// GoldenDir sets the directory golden files are kept in. It defaults to
+// testdata, next to the test.
+func GoldenDir(dir string) GoldenOption {
+	return func(c *goldenConfig) {
+		c.dir = dir
+	}
+}
+
+// Redact replaces the values at the given property paths with Redacted
+// before the payload is compared or saved. Use it for IDs, timestamps and
+// anything else that changes between runs. Paths that don't exist in the
+// payload are left alone.
+func Redact(propertyPaths ...string) GoldenOption {
+	return func(c *goldenConfig) {
+		c.redact = append(c.redact, propertyPaths...)
+	}
+}
+
+// UpdateGolden rewrites the golden file with the payload instead of
+// comparing against it when update is true. It overrides -update and
+// UpdateEnv.
+func UpdateGolden(update bool) GoldenOption {
+	return func(c *goldenConfig) {
+		c.update = update
+	}
+}
+
+// NormalizeGolden normalizes the payload, after redacting it, before it's
+// compared or saved
+func NormalizeGolden(n *Normalizer) GoldenOption {
+	return func(c *goldenConfig) {
+		c.normalizer = n
+	}
+}
+
+// MatchGolden compares a JSON payload with the golden file saved under
+// name. Golden files hold canonical JSON, pretty-printed, so the same
+// payload always produces the same file and changes diff well in code
+// review. When they don't match, the structural
+// differences are reported to t.
+//
+// Run the tests with -update to create or rewrite the golden files. The
+// flag is read from the command line whether UpdateFlag registered it or
+// the package did. UpdateEnv and UpdateGolden do the same.
+func MatchGolden(t TestingT, name string, payload []byte, opts ...GoldenOption) {
+	t.Helper()
+
+	update, _ := strconv.ParseBool(os.Getenv(UpdateEnv))
+	if flagged, ok := updateFlag(); ok && flagged {
+		update = true
+	}
+
+	cfg := goldenConfig{dir: "testdata", update: update}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	actual, err := Unmarshal(payload)
+	if err != nil {
+		t.Errorf("golden %s: failed to parse payload: %v", name, err)
+		return
+	}
+
+	for _, p := range cfg.redact {
+		_ = setPathValue(actual, p, Redacted)
+	}
+
+	if cfg.normalizer != nil {
+		actual = cfg.normalizer.Normalize(actual)
+	}
+
+	file := filepath.Join(cfg.dir, name+".golden.json")
+	if cfg.update {
+		if err := writeGolden(file, actual); err != nil {
+			t.Errorf("golden %s: %v", name, err)
+		}
+
+		return
+	}
+
+	b, err := os.ReadFile(file)
+	if os.IsNotExist(err) {
+		t.Errorf("golden file %s does not exist; run the tests with -update to create it", file)
+		return
+	}
+	if err != nil {
+		t.Errorf("golden %s: %v", name, err)
+		return
+	}
+
+	expected, err := Unmarshal(b)
+	if err != nil {
+		t.Errorf("golden file %s is not valid JSON: %v", file, err)
+		return
+	}
+
+	if err := diff.JSON(map[string]interface{}(expected), map[string]interface{}(actual)); err != nil {
+		t.Errorf("payload does not match golden file %s (A is the golden file, B the payload):\n%v", file, err)
+	}
+}
+
+func writeGolden(file string, obj Object) error {
+	canonical, err := MarshalCanonical(obj)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, canonical, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, buf.Bytes(), 0644)
+}
@@ -0,0 +1,140 @@
+package jsont
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchGolden(tt *testing.T) {
+	dir := tt.TempDir()
+	golden := `{
+  "id": "<redacted>",
+  "user": {
+    "name": "bob",
+    "roles": [
+      "admin"
+    ]
+  }
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "user.golden.json"), []byte(golden), 0644); err != nil {
+		tt.Fatal(err)
+	}
+
+	testcases := []struct {
+		name, golden, payload string
+		reported              string
+	}{
+		{
+			name:     "passes when the payload matches the golden file",
+			golden:   "user",
+			payload:  `{"user": {"roles": ["admin"], "name": "bob"}, "id": "usr_123"}`,
+			reported: "",
+		},
+		{
+			name:     "reports structural differences",
+			golden:   "user",
+			payload:  `{"user": {"roles": ["admin", "dev"], "name": "al"}, "id": "usr_123"}`,
+			reported: "user.name: A has \"bob\"; B has \"al\"\nuser.roles[1]: only in B: \"dev\"",
+		},
+		{
+			name:     "reports missing golden files",
+			golden:   "missing",
+			payload:  `{}`,
+			reported: "run the tests with -update to create it",
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			ft := &fakeT{}
+			MatchGolden(ft, tc.golden, []byte(tc.payload), GoldenDir(dir), Redact("id"))
+
+			if tc.reported == "" {
+				if len(ft.errs) > 0 {
+					t.Errorf("expected no reported failures, got %v", ft.errs)
+				}
+				return
+			}
+
+			if len(ft.errs) != 1 || !strings.Contains(ft.errs[0], tc.reported) {
+				t.Errorf("expected a failure containing %q, got %v", tc.reported, ft.errs)
+			}
+		})
+	}
+}
+
+func TestMatchGolden_Update(tt *testing.T) {
+	testcases := []struct {
+		name string
+		env  string
+		opts []GoldenOption
+	}{
+		{
+			name: "updates when asked to by an option",
+			opts: []GoldenOption{UpdateGolden(true)},
+		},
+		{
+			name: "updates when asked to by the environment",
+			env:  "1",
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			t.Setenv(UpdateEnv, tc.env)
+			dir := t.TempDir()
+
+			ft := &fakeT{}
+			MatchGolden(ft, "created", []byte(`{"b": 1, "a": "<p>"}`), append(tc.opts, GoldenDir(dir))...)
+			if len(ft.errs) > 0 {
+				t.Fatalf("expected no reported failures, got %v", ft.errs)
+			}
+
+			b, err := os.ReadFile(filepath.Join(dir, "created.golden.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := "{\n  \"a\": \"<p>\",\n  \"b\": 1\n}\n"
+			if string(b) != expected {
+				t.Errorf("expected golden file:\n%s\ngot:\n%s", expected, b)
+			}
+		})
+	}
+
+	tt.Run("updates when asked to by the -update flag", func(t *testing.T) {
+		UpdateFlag()
+		UpdateFlag()
+		if err := flag.Set("update", "true"); err != nil {
+			t.Fatal(err)
+		}
+		defer flag.Set("update", "false")
+
+		dir := t.TempDir()
+
+		ft := &fakeT{}
+		MatchGolden(ft, "created", []byte(`{}`), GoldenDir(dir))
+		if len(ft.errs) > 0 {
+			t.Fatalf("expected no reported failures, got %v", ft.errs)
+		}
+
+		if _, err := os.Stat(filepath.Join(dir, "created.golden.json")); err != nil {
+			t.Errorf("expected the golden file to be created: %v", err)
+		}
+	})
+
+	tt.Run("the option overrides the environment", func(t *testing.T) {
+		t.Setenv(UpdateEnv, "1")
+		dir := t.TempDir()
+
+		ft := &fakeT{}
+		MatchGolden(ft, "created", []byte(`{}`), GoldenDir(dir), UpdateGolden(false))
+		if len(ft.errs) != 1 {
+			t.Errorf("expected the missing golden file to be reported, got %v", ft.errs)
+		}
+	})
+}
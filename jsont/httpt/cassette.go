@@ -0,0 +1,358 @@
+package httpt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+// Cassette is an http.RoundTripper that records the requests a client
+// makes, and the responses it gets, to a file. Once the file exists the
+// responses are replayed from it and nothing is sent over the network.
+//
+// Recorded requests are matched on method, URL and body. JSON bodies are
+// compared in canonical form, so key order and whitespace don't matter,
+// and the values at ignored paths aren't compared at all. Request headers
+// aren't recorded, and response headers that carry credentials, like
+// Set-Cookie, are saved as jsont.Redacted, so secrets don't end up in the
+// file.
+//
+//	c, err := httpt.NewCassette("testdata/users.cassette.json", httpt.IgnorePaths("requestId"))
+//	...
+//	defer c.Save()
+//	client := &http.Client{Transport: c}
+type Cassette struct {
+	filename  string
+	next      http.RoundTripper
+	ignore    []string
+	redact    map[string]bool
+	recording bool
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Interaction is one request and the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request saved in a cassette. JSON bodies are kept
+// in JSON so the file stays readable; other bodies are kept in Body.
+type RecordedRequest struct {
+	Method string          `json:"method"`
+	URL    string          `json:"url"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   string          `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response saved in a cassette
+type RecordedResponse struct {
+	Status int             `json:"status"`
+	Header http.Header     `json:"header,omitempty"`
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Body   string          `json:"body,omitempty"`
+}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// CassetteOption configures a Cassette
+type CassetteOption func(*Cassette)
+
+// IgnorePaths leaves the values at property paths in JSON request bodies
+// out when matching requests. Use it for IDs, timestamps and anything else
+// that changes between runs.
+func IgnorePaths(propertyPaths ...string) CassetteOption {
+	return func(c *Cassette) {
+		c.ignore = append(c.ignore, propertyPaths...)
+	}
+}
+
+// DefaultRedactedHeaders are the response headers a Cassette redacts
+// unless told otherwise
+var DefaultRedactedHeaders = []string{
+	"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie",
+	"X-Api-Key", "X-Auth-Token",
+}
+
+// RedactHeaders redacts more response headers when recording, on top of
+// DefaultRedactedHeaders
+func RedactHeaders(names ...string) CassetteOption {
+	return func(c *Cassette) {
+		for _, name := range names {
+			c.redact[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+}
+
+// KeepHeaders records response headers as they are, even if they're in
+// DefaultRedactedHeaders. Use it when a test needs to replay a cookie.
+func KeepHeaders(names ...string) CassetteOption {
+	return func(c *Cassette) {
+		for _, name := range names {
+			delete(c.redact, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+// Transport sets the RoundTripper requests are sent with while recording.
+// It defaults to http.DefaultTransport.
+func Transport(rt http.RoundTripper) CassetteOption {
+	return func(c *Cassette) {
+		c.next = rt
+	}
+}
+
+// NewCassette loads the cassette saved in filename and replays it. If the
+// file doesn't exist, the Cassette records instead; call Save to write it.
+func NewCassette(filename string, opts ...CassetteOption) (*Cassette, error) {
+	c := &Cassette{filename: filename, next: http.DefaultTransport, redact: make(map[string]bool)}
+	for _, name := range DefaultRedactedHeaders {
+		c.redact[http.CanonicalHeaderKey(name)] = true
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	b, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		c.recording = true
+		return c, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read cassette")
+	}
+
+	var f cassetteFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse cassette %s", filename)
+	}
+
+	c.interactions = f.Interactions
+	c.used = make([]bool, len(f.Interactions))
+
+	return c, nil
+}
+
+// Recording reports whether the Cassette is recording rather than
+// replaying
+func (c *Cassette) Recording() bool {
+	return c.recording
+}
+
+// RoundTrip replays the recorded response for req or, when recording,
+// sends a copy of req and records what comes back. Each recorded
+// interaction is replayed once before any is replayed again. Like any
+// RoundTripper, it closes req's body and leaves req itself unchanged.
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.recording {
+		return c.record(req, body)
+	}
+
+	key, err := c.matchKey(req.Method, req.URL.String(), body)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	found := -1
+	for i, in := range c.interactions {
+		recorded, err := c.matchKey(in.Request.Method, in.Request.URL, in.Request.body())
+		if err != nil || recorded != key {
+			continue
+		}
+
+		if !c.used[i] {
+			found = i
+			break
+		}
+
+		if found < 0 {
+			found = i
+		}
+	}
+
+	if found < 0 {
+		return nil, errors.Errorf("cassette %s has no interaction matching %s %s", c.filename, req.Method, req.URL)
+	}
+
+	c.used[found] = true
+
+	return c.interactions[found].Response.response(req), nil
+}
+
+// Save writes the recorded interactions to the cassette's file. It does
+// nothing when replaying.
+func (c *Cassette) Save() error {
+	if !c.recording {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	b, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cassette")
+	}
+
+	return os.WriteFile(c.filename, append(b, '\n'), 0644)
+}
+
+func (c *Cassette) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	res, err := c.next.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	res.Request = req
+
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read response body")
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	in := Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: req.URL.String()},
+		Response: RecordedResponse{Status: res.StatusCode, Header: res.Header.Clone()},
+	}
+	in.Request.JSON, in.Request.Body = splitBody(body)
+	in.Response.JSON, in.Response.Body = splitBody(resBody)
+
+	// the body may be compacted, so its length isn't worth keeping
+	in.Response.Header.Del("Content-Length")
+	for name := range in.Response.Header {
+		if c.redact[name] {
+			in.Response.Header[name] = []string{jsont.Redacted}
+		}
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.mu.Unlock()
+
+	return res, nil
+}
+
+// matchKey is what requests are matched on: method, URL and the body, in
+// canonical form if it's JSON, with the ignored paths removed if it's a
+// JSON object. Bodies that aren't JSON are matched byte for byte.
+func (c *Cassette) matchKey(method, url string, body []byte) (string, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Sprintf("%s %s\n%s", method, url, body), nil
+	}
+
+	if m, ok := v.(map[string]interface{}); ok {
+		obj := jsont.Set(m)
+		for _, p := range c.ignore {
+			_ = obj.Delete(p)
+		}
+	}
+
+	canonical, err := jsont.MarshalCanonical(v)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to canonicalize request body")
+	}
+
+	return fmt.Sprintf("%s %s\n%s", method, url, canonical), nil
+}
+
+func (r RecordedRequest) body() []byte {
+	if len(r.JSON) > 0 {
+		return r.JSON
+	}
+
+	return []byte(r.Body)
+}
+
+func (r RecordedResponse) response(req *http.Request) *http.Response {
+	body := []byte(r.Body)
+	if len(r.JSON) > 0 {
+		// undo the indenting the cassette file was saved with
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, r.JSON); err == nil {
+			body = buf.Bytes()
+		}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// readBody reads a request's body, from a fresh copy when the request can
+// make one, and closes it
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	defer req.Body.Close()
+
+	rc := req.Body
+	if req.GetBody != nil {
+		var err error
+		if rc, err = req.GetBody(); err != nil {
+			return nil, errors.Wrap(err, "failed to copy request body")
+		}
+		defer rc.Close()
+	}
+
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read request body")
+	}
+
+	return body, nil
+}
+
+// splitBody keeps valid JSON as JSON and everything else as a string
+func splitBody(body []byte) (json.RawMessage, string) {
+	if len(body) > 0 && json.Valid(body) {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err == nil {
+			return buf.Bytes(), ""
+		}
+	}
+
+	return nil, string(body)
+}
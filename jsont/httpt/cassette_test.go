@@ -0,0 +1,228 @@
+package httpt
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+// offline fails every request, to prove a cassette is replaying
+type offline struct{}
+
+func (offline) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network is not available")
+}
+
+func TestCassette(tt *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits": ` + strconv.Itoa(hits) + `, "sent": ` + string(body) + `}`))
+	}))
+	defer srv.Close()
+
+	filename := filepath.Join(tt.TempDir(), "users.cassette.json")
+
+	recorder, err := NewCassette(filename, IgnorePaths("meta.requestId"))
+	if err != nil {
+		tt.Fatalf("failed to create cassette: %v", err)
+	}
+
+	if !recorder.Recording() {
+		tt.Fatal("expected a new cassette to record")
+	}
+
+	client := &http.Client{Transport: recorder}
+	for _, body := range []string{`{"name": "bob", "meta": {"requestId": "r1"}}`, `{"name": "alice"}`, `[1, 2]`} {
+		if _, err := post(client, srv.URL+"/users", body); err != nil {
+			tt.Fatalf("failed to record: %v", err)
+		}
+	}
+
+	if err := recorder.Save(); err != nil {
+		tt.Fatalf("failed to save cassette: %v", err)
+	}
+
+	player, err := NewCassette(filename, IgnorePaths("meta.requestId"), Transport(offline{}))
+	if err != nil {
+		tt.Fatalf("failed to load cassette: %v", err)
+	}
+
+	if player.Recording() {
+		tt.Fatal("expected a saved cassette to replay")
+	}
+
+	client = &http.Client{Transport: player}
+
+	testcases := []struct {
+		name     string
+		body     string
+		expected string
+		err      error
+	}{
+		{
+			name:     "replays a request matched by canonical body",
+			body:     `{"meta": {"requestId": "r2"}, "name": "bob"}`,
+			expected: `{"hits":1,"sent":{"name":"bob","meta":{"requestId":"r1"}}}`,
+		},
+		{
+			name:     "replays requests whatever their order",
+			body:     `{"name":"alice"}`,
+			expected: `{"hits":2,"sent":{"name":"alice"}}`,
+		},
+		{
+			name:     "replays requests with bodies that aren't objects",
+			body:     `[ 1,2 ]`,
+			expected: `{"hits":3,"sent":[1,2]}`,
+		},
+		{
+			name: "fails requests that weren't recorded",
+			body: `{"name": "carol"}`,
+			err:  errors.New(`Post "` + srv.URL + `/users": cassette ` + filename + ` has no interaction matching POST ` + srv.URL + `/users`),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			got, err := post(client, srv.URL+"/users", tc.body)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && got != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+
+	if hits != 3 {
+		tt.Errorf("expected the server to be hit only while recording, got %d hits", hits)
+	}
+}
+
+func TestCassette_LeavesRequestsUnchanged(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	c, err := NewCassette(filepath.Join(t.TempDir(), "echo.cassette.json"))
+	if err != nil {
+		t.Fatalf("failed to create cassette: %v", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(`{"name": "bob"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := req.Body
+
+	res, err := c.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+	defer res.Body.Close()
+
+	if req.Body != body {
+		t.Error("expected the request's body to be left in place")
+	}
+
+	if got, _ := io.ReadAll(res.Body); string(got) != `{"name": "bob"}` {
+		t.Errorf("expected the body to be sent, got %s", got)
+	}
+}
+
+func TestCassette_RedactsHeaders(tt *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=s3cr3t")
+		w.Header().Set("X-Tenant", "acme")
+		w.Header().Set("X-Trace", "t1")
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	testcases := []struct {
+		name     string
+		opts     []CassetteOption
+		expected map[string]string
+	}{
+		{
+			name:     "redacts credentials by default",
+			expected: map[string]string{"Set-Cookie": "<redacted>", "X-Tenant": "acme", "X-Trace": "t1"},
+		},
+		{
+			name:     "redacts more headers when asked",
+			opts:     []CassetteOption{RedactHeaders("x-tenant")},
+			expected: map[string]string{"Set-Cookie": "<redacted>", "X-Tenant": "<redacted>", "X-Trace": "t1"},
+		},
+		{
+			name:     "keeps headers when asked",
+			opts:     []CassetteOption{KeepHeaders("set-cookie")},
+			expected: map[string]string{"Set-Cookie": "session=s3cr3t", "X-Tenant": "acme", "X-Trace": "t1"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "headers.cassette.json")
+
+			recorder, err := NewCassette(filename, tc.opts...)
+			if err != nil {
+				t.Fatalf("failed to create cassette: %v", err)
+			}
+
+			res, err := (&http.Client{Transport: recorder}).Get(srv.URL)
+			if err != nil {
+				t.Fatalf("failed to record: %v", err)
+			}
+			res.Body.Close()
+
+			if got := res.Header.Get("Set-Cookie"); got != "session=s3cr3t" {
+				t.Errorf("expected the live response to keep its cookie, got %q", got)
+			}
+
+			if err := recorder.Save(); err != nil {
+				t.Fatalf("failed to save cassette: %v", err)
+			}
+
+			player, err := NewCassette(filename, Transport(offline{}))
+			if err != nil {
+				t.Fatalf("failed to load cassette: %v", err)
+			}
+
+			res, err = (&http.Client{Transport: player}).Get(srv.URL)
+			if err != nil {
+				t.Fatalf("failed to replay: %v", err)
+			}
+			res.Body.Close()
+
+			for name, value := range tc.expected {
+				if got := res.Header.Get(name); got != value {
+					t.Errorf("expected %s to be %q, got %q", name, value, got)
+				}
+			}
+		})
+	}
+}
+
+func post(client *http.Client, url, body string) (string, error) {
+	res, err := client.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	b, err := io.ReadAll(res.Body)
+	return string(b), err
+}
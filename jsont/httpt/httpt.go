@@ -0,0 +1,155 @@
+// Package httpt makes assertions about HTTP responses, such as the ones
+// an httptest.Server or httptest.ResponseRecorder produce, and hands
+// their JSON bodies over to jsont. Its Server stubs out the APIs a client
+// under test talks to.
+//
+//	res := httpt.Recorded(t, rec).
+//		Status(http.StatusOK).
+//		ContentType("application/json")
+//	res.JSON().Path("user.name").Equals("bob")
+package httpt
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+// maxBodyInMessage caps how much of a body is quoted in a failure message
+const maxBodyInMessage = 512
+
+// Response holds an HTTP response and its body so assertions can be made
+// about them. Failed assertions are reported to t straight away and don't
+// stop the chain.
+type Response struct {
+	t       jsont.TestingT
+	res     *http.Response
+	body    []byte
+	readErr error
+}
+
+// New reads res's body and closes it. The body is put back with the same
+// bytes, so res can still be read afterwards.
+func New(t jsont.TestingT, res *http.Response) *Response {
+	t.Helper()
+
+	r := &Response{t: t, res: res}
+	if res.Body != nil {
+		r.body, r.readErr = io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if r.readErr != nil {
+			t.Errorf("failed to read response body: %v", r.readErr)
+		}
+	}
+
+	res.Body = io.NopCloser(bytes.NewReader(r.body))
+
+	return r
+}
+
+// Recorded is New for the response an httptest.ResponseRecorder recorded
+func Recorded(t jsont.TestingT, rec *httptest.ResponseRecorder) *Response {
+	t.Helper()
+
+	return New(t, rec.Result())
+}
+
+// Status asserts the response's status code
+func (r *Response) Status(code int) *Response {
+	r.t.Helper()
+
+	if r.res.StatusCode != code {
+		r.t.Errorf("wanted status %d; got %d\nbody: %s", code, r.res.StatusCode, r.quotedBody())
+	}
+
+	return r
+}
+
+// Header asserts the response has a header with value. Other values of
+// the same header are allowed.
+func (r *Response) Header(key, value string) *Response {
+	r.t.Helper()
+
+	values := r.res.Header.Values(key)
+	for _, v := range values {
+		if v == value {
+			return r
+		}
+	}
+
+	if len(values) == 0 {
+		r.t.Errorf("wanted header %s: %q; it was not set", key, value)
+	} else {
+		r.t.Errorf("wanted header %s: %q; got %q", key, value, values)
+	}
+
+	return r
+}
+
+// ContentType asserts the response's media type. Parameters, like charset,
+// are ignored.
+func (r *Response) ContentType(mediaType string) *Response {
+	r.t.Helper()
+
+	header := r.res.Header.Get("Content-Type")
+	got, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		r.t.Errorf("wanted Content-Type %s; got %q: %v", mediaType, header, err)
+		return r
+	}
+
+	if got != mediaType {
+		r.t.Errorf("wanted Content-Type %s; got %s", mediaType, got)
+	}
+
+	return r
+}
+
+// Body returns the response's body
+func (r *Response) Body() []byte {
+	return r.body
+}
+
+// Object decodes the body into a jsont.Object, reporting it if the body
+// isn't a JSON object. An empty Object is returned in that case.
+func (r *Response) Object() jsont.Object {
+	r.t.Helper()
+
+	if r.readErr != nil {
+		return jsont.Object{}
+	}
+
+	obj, err := jsont.Unmarshal(r.body)
+	if err != nil {
+		r.t.Errorf("response body is not a JSON object: %v\nbody: %s", err, r.quotedBody())
+		return jsont.Object{}
+	}
+
+	// null unmarshals into a nil map without an error
+	if obj == nil {
+		r.t.Errorf("response body is not a JSON object\nbody: %s", r.quotedBody())
+		return jsont.Object{}
+	}
+
+	return obj
+}
+
+// JSON starts a jsont.Expect chain against the body
+func (r *Response) JSON() *jsont.Expectation {
+	r.t.Helper()
+
+	return jsont.Expect(r.t, r.Object())
+}
+
+func (r *Response) quotedBody() string {
+	if len(r.body) > maxBodyInMessage {
+		return string(r.body[:maxBodyInMessage]) + "..."
+	}
+
+	return string(r.body)
+}
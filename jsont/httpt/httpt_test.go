@@ -0,0 +1,134 @@
+package httpt
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func userHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/users/none" {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, "null")
+		return
+	}
+
+	if r.URL.Path != "/users/1" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, "not found")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Add("X-Request-Id", "abc")
+	io.WriteString(w, `{"user": {"name": "bob", "roles": ["admin"]}}`)
+}
+
+func TestResponse(tt *testing.T) {
+	testcases := []struct {
+		name   string
+		url    string
+		assert func(r *Response)
+		errs   []string
+	}{
+		{
+			name: "passes assertions that hold",
+			url:  "/users/1",
+			assert: func(r *Response) {
+				r.Status(http.StatusOK).
+					ContentType("application/json").
+					Header("X-Request-Id", "abc").
+					JSON().Path("user.name").Equals("bob")
+			},
+		},
+		{
+			name: "reports a status, header and content type that don't match",
+			url:  "/users/2",
+			assert: func(r *Response) {
+				r.Status(http.StatusOK).
+					ContentType("application/json").
+					Header("X-Request-Id", "abc")
+			},
+			errs: []string{
+				"wanted status 200; got 404\nbody: not found",
+				"wanted Content-Type application/json; got text/plain",
+				`wanted header X-Request-Id: "abc"; it was not set`,
+			},
+		},
+		{
+			name: "reports failed path assertions when the test finishes",
+			url:  "/users/1",
+			assert: func(r *Response) {
+				r.JSON().Path("user.name").Equals("alice")
+			},
+			errs: []string{"failed expectations:\nuser.name: wanted \"alice\"; got \"bob\""},
+		},
+		{
+			name: "reports bodies that aren't JSON objects",
+			url:  "/missing",
+			assert: func(r *Response) {
+				r.Object()
+			},
+			errs: []string{"response body is not a JSON object: invalid character 'o' in literal null (expecting 'u')\nbody: not found"},
+		},
+		{
+			name: "reports null bodies",
+			url:  "/users/none",
+			assert: func(r *Response) {
+				r.Object()
+			},
+			errs: []string{"response body is not a JSON object\nbody: null"},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(userHandler))
+	defer srv.Close()
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			res, err := http.Get(srv.URL + tc.url)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+
+			ft := &fakeT{}
+			tc.assert(New(ft, res))
+			ft.finish()
+
+			if len(ft.errs) != len(tc.errs) {
+				t.Fatalf("expected %d failures, got %d: %q", len(tc.errs), len(ft.errs), ft.errs)
+			}
+
+			for i, e := range tc.errs {
+				if !strings.HasPrefix(ft.errs[i], e) {
+					t.Errorf("expected failure %d to start with %q, got %q", i, e, ft.errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestNew_RestoresBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	userHandler(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	resp := rec.Result()
+
+	ft := &fakeT{}
+	r := New(ft, resp).Status(http.StatusOK)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read the restored body: %v", err)
+	}
+
+	if string(body) != string(r.Body()) || !strings.Contains(string(body), `"name": "bob"`) {
+		t.Errorf("expected the body to be restored, got %s", body)
+	}
+
+	if len(ft.errs) > 0 {
+		t.Errorf("expected no failures, got %q", ft.errs)
+	}
+}
@@ -0,0 +1,267 @@
+package httpt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+// Server is a stub HTTP server. Requests are answered by the first Route
+// that matches them, and every request is recorded as a Call. Requests no
+// Route matches get a 404 and are reported as failures.
+//
+//	srv := httpt.NewServer(t)
+//	srv.On(http.MethodPost, "/users").
+//		Body(jsont.Object{"name": jsont.AnyString()}).
+//		ReplyFile(http.StatusCreated, "testdata/user.json").
+//		ExpectCalls(1)
+type Server struct {
+	*httptest.Server
+
+	t      jsont.TestingT
+	mu     sync.Mutex
+	routes []*Route
+	calls  []Call
+}
+
+// Call is a request the Server received. Object is the decoded body, if
+// the body was a JSON object.
+type Call struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Object jsont.Object
+}
+
+// NewServer starts a Server, which is closed when the test finishes
+func NewServer(t jsont.TestingT) *Server {
+	t.Helper()
+
+	s := &Server{t: t}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// On registers a Route for requests with method whose path matches
+// pattern. Patterns use path.Match syntax, so "/users/*" matches
+// "/users/1".
+func (s *Server) On(method, pattern string) *Route {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := &Route{
+		server:  s,
+		method:  method,
+		pattern: pattern,
+		query:   url.Values{},
+		status:  http.StatusOK,
+		header:  http.Header{},
+	}
+	s.routes = append(s.routes, r)
+
+	return r
+}
+
+// Calls returns every request the Server has received, in order
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		s.t.Errorf("failed to read request body for %s %s: %v", req.Method, req.URL.Path, err)
+	}
+
+	call := Call{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}
+
+	if obj, err := jsont.Unmarshal(body); err == nil {
+		call.Object = obj
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call)
+
+	var route *Route
+	for _, r := range s.routes {
+		if r.matches(call) {
+			route = r
+			r.calls = append(r.calls, call)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if route == nil {
+		s.t.Errorf("no route matches %s %s\nbody: %s", call.Method, req.URL.RequestURI(), body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{
+			"error": fmt.Sprintf("no route matches %s %s", call.Method, call.Path),
+		})
+
+		return
+	}
+
+	for k, v := range route.header {
+		w.Header()[k] = v
+	}
+
+	w.WriteHeader(route.status)
+	w.Write(route.body)
+}
+
+// Route decides which requests it answers and how. Its methods configure
+// it and can be chained.
+type Route struct {
+	server   *Server
+	method   string
+	pattern  string
+	query    url.Values
+	template jsont.Object
+	opts     []jsont.MatchOption
+
+	status int
+	header http.Header
+	body   []byte
+
+	calls []Call
+}
+
+// Query requires the request to have a query parameter with value
+func (r *Route) Query(key, value string) *Route {
+	r.query.Add(key, value)
+	return r
+}
+
+// Body requires the request body to be a JSON object containing template,
+// the way jsont.Contains checks it, so Matchers can be used.
+func (r *Route) Body(template jsont.Object, opts ...jsont.MatchOption) *Route {
+	r.template = template
+	r.opts = opts
+
+	return r
+}
+
+// Header sets a header on the response
+func (r *Route) Header(key, value string) *Route {
+	r.header.Add(key, value)
+	return r
+}
+
+// Reply answers with status and body. A []byte body is sent as is;
+// anything else is marshaled as JSON.
+func (r *Route) Reply(status int, body interface{}) *Route {
+	r.server.t.Helper()
+
+	r.status = status
+	if b, ok := body.([]byte); ok {
+		r.body = b
+	} else {
+		b, err := json.Marshal(body)
+		if err != nil {
+			r.server.t.Errorf("failed to marshal reply for %s %s: %v", r.method, r.pattern, err)
+		}
+
+		r.body = b
+	}
+
+	if r.header.Get("Content-Type") == "" {
+		r.header.Set("Content-Type", "application/json")
+	}
+
+	return r
+}
+
+// ReplyFile answers with status and the contents of a fixture file
+func (r *Route) ReplyFile(status int, filename string) *Route {
+	r.server.t.Helper()
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		r.server.t.Errorf("failed to read fixture for %s %s: %v", r.method, r.pattern, err)
+	}
+
+	return r.Reply(status, b)
+}
+
+// Calls returns the requests this Route has answered, in order
+func (r *Route) Calls() []Call {
+	r.server.mu.Lock()
+	defer r.server.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// ExpectCalls asserts, when the test finishes, that the Route answered n
+// requests.
+func (r *Route) ExpectCalls(n int) *Route {
+	t := r.server.t
+	t.Helper()
+
+	t.Cleanup(func() {
+		t.Helper()
+
+		if got := len(r.Calls()); got != n {
+			t.Errorf("%s %s: wanted %d calls; got %d", r.method, r.pattern, n, got)
+		}
+	})
+
+	return r
+}
+
+func (r *Route) matches(c Call) bool {
+	if r.method != c.Method {
+		return false
+	}
+
+	if ok, _ := path.Match(r.pattern, c.Path); !ok {
+		return false
+	}
+
+	for key, values := range r.query {
+		for _, v := range values {
+			if !contains(c.Query[key], v) {
+				return false
+			}
+		}
+	}
+
+	if r.template != nil {
+		return c.Object != nil && jsont.Contains(c.Object, r.template, r.opts...) == nil
+	}
+
+	return true
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
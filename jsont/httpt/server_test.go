@@ -0,0 +1,92 @@
+package httpt
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+func TestServer(t *testing.T) {
+	ft := &fakeT{}
+	srv := NewServer(ft)
+
+	create := srv.On(http.MethodPost, "/users").
+		Body(jsont.Object{"name": jsont.AnyString()}).
+		ReplyFile(http.StatusCreated, "testdata/user.json").
+		ExpectCalls(1)
+	search := srv.On(http.MethodGet, "/users").
+		Query("role", "admin").
+		Reply(http.StatusOK, jsont.Object{"users": []interface{}{}}).
+		ExpectCalls(2)
+	get := srv.On(http.MethodGet, "/users/*").
+		Header("X-Request-Id", "abc").
+		Reply(http.StatusOK, []byte(`{"id": "u2"}`))
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+		status int
+		reply  string
+	}{
+		{method: http.MethodPost, path: "/users", body: `{"name": "bob", "age": 3}`, status: http.StatusCreated, reply: `{"id": "u1", "name": "bob"}` + "\n"},
+		{method: http.MethodGet, path: "/users?role=admin", status: http.StatusOK, reply: `{"users":[]}`},
+		{method: http.MethodGet, path: "/users/u2", status: http.StatusOK, reply: `{"id": "u2"}`},
+		{method: http.MethodPost, path: "/users", body: `{"name": 3}`, status: http.StatusNotFound, reply: `{"error":"no route matches POST /users"}` + "\n"},
+	}
+
+	for _, r := range requests {
+		req, err := http.NewRequest(r.method, srv.URL+r.path, strings.NewReader(r.body))
+		if err != nil {
+			t.Fatalf("failed to build request: %v", err)
+		}
+
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+
+		if res.StatusCode != r.status || string(body) != r.reply {
+			t.Errorf("%s %s: expected %d %s, got %d %s", r.method, r.path, r.status, r.reply, res.StatusCode, body)
+		}
+	}
+
+	if calls := create.Calls(); len(calls) != 1 || calls[0].Object["age"] != float64(3) {
+		t.Errorf("expected one recorded call with the decoded body, got %+v", calls)
+	}
+
+	if calls := get.Calls(); len(calls) != 1 || calls[0].Path != "/users/u2" {
+		t.Errorf("expected one recorded call to /users/u2, got %+v", calls)
+	}
+
+	if calls := srv.Calls(); len(calls) != 4 {
+		t.Errorf("expected the server to record 4 calls, got %d", len(calls))
+	}
+
+	ft.finish()
+
+	expected := []string{
+		"no route matches POST /users\nbody: {\"name\": 3}",
+		"GET /users: wanted 2 calls; got 1",
+	}
+
+	if len(ft.errs) != len(expected) {
+		t.Fatalf("expected %d failures, got %q", len(expected), ft.errs)
+	}
+
+	for i, e := range expected {
+		if ft.errs[i] != e {
+			t.Errorf("expected failure %q, got %q", e, ft.errs[i])
+		}
+	}
+
+	if len(search.Calls()) != 1 {
+		t.Errorf("expected the search route to be called once, got %d", len(search.Calls()))
+	}
+}
@@ -0,0 +1,85 @@
+package jsont
+
+import "sort"
+
+// SchemaDialect is the JSON Schema draft InferSchema writes
+const SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// InferSchema builds a JSON Schema (draft 2020-12) that every sample is
+// valid against. It works out:
+//
+//   - the types seen at each path, with integer and number merged
+//   - required properties, which are the ones present in every sample
+//   - enums, for strings and integers that only ever took a few values,
+//     each seen more than once
+//   - one items schema per array, merged from all of its elements
+//
+// The result is a starting point for a contract test, not a finished
+// schema. It can be compiled with the jsont/schema package.
+func InferSchema(samples ...Object) Object {
+	s := newShape()
+	for _, sample := range samples {
+		s.add(map[string]interface{}(sample))
+	}
+
+	schema := inferSchema(s)
+	schema["$schema"] = SchemaDialect
+
+	return Set(schema)
+}
+
+func inferSchema(s *shape) map[string]interface{} {
+	schema := make(map[string]interface{})
+
+	types := s.typeNames()
+	switch len(types) {
+	case 0:
+		// nothing was ever seen here, such as the items of an empty array
+		return schema
+	case 1:
+		schema["type"] = types[0]
+	default:
+		list := make([]interface{}, len(types))
+		for i, t := range types {
+			list[i] = t
+		}
+		schema["type"] = list
+	}
+
+	if s.isEnum() {
+		enum := append([]interface{}{}, s.scalars...)
+		if s.types["null"] {
+			enum = append(enum, nil)
+		}
+		schema["enum"] = enum
+	}
+
+	if s.props != nil {
+		names := make([]string, 0, len(s.props))
+		for name := range s.props {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		props := make(map[string]interface{}, len(names))
+		var required []interface{}
+		for _, name := range names {
+			props[name] = inferSchema(s.props[name])
+
+			if s.required(name) {
+				required = append(required, name)
+			}
+		}
+
+		schema["properties"] = props
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+	}
+
+	if s.items != nil && s.items.seen > 0 {
+		schema["items"] = inferSchema(s.items)
+	}
+
+	return schema
+}
@@ -0,0 +1,134 @@
+package jsont
+
+import (
+	"testing"
+
+	"github.com/dedwardstech/test/diff"
+)
+
+func TestInferSchema(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		samples  []string
+		expected string
+	}{
+		{
+			name:    "infers types and required keys from a single sample",
+			samples: []string{`{"id": 1, "name": "bob", "score": 1.5, "admin": false, "tags": [], "extra": null}`},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"admin": {"type": "boolean"},
+					"extra": {"type": "null"},
+					"id": {"type": "integer"},
+					"name": {"type": "string"},
+					"score": {"type": "number"},
+					"tags": {"type": "array"}
+				},
+				"required": ["admin", "extra", "id", "name", "score", "tags"]
+			}`,
+		},
+		{
+			name: "only requires keys present in every sample",
+			samples: []string{
+				`{"id": 1, "nick": "b", "age": 3}`,
+				`{"id": 2, "age": 3.5}`,
+				`{"id": 3, "nick": null}`,
+			},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"age": {"type": "number"},
+					"id": {"type": "integer"},
+					"nick": {"type": ["null", "string"]}
+				},
+				"required": ["id"]
+			}`,
+		},
+		{
+			name: "infers enums for small repeated value sets",
+			samples: []string{
+				`{"status": "active", "name": "a"}`,
+				`{"status": "banned", "name": "b"}`,
+				`{"status": "active", "name": "c"}`,
+				`{"status": "banned", "name": "d"}`,
+			},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"status": {"type": "string", "enum": ["active", "banned"]}
+				},
+				"required": ["name", "status"]
+			}`,
+		},
+		{
+			name: "needs every value to repeat before inferring an enum",
+			samples: []string{
+				`{"status": "active"}`,
+				`{"status": "active"}`,
+				`{"status": "banned"}`,
+			},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"status": {"type": "string"}
+				},
+				"required": ["status"]
+			}`,
+		},
+		{
+			name: "merges array items into one schema",
+			samples: []string{
+				`{"users": [{"id": 1, "email": "a@b.c"}, {"id": 2}]}`,
+				`{"users": [{"id": 3, "name": "x"}]}`,
+			},
+			expected: `{
+				"$schema": "https://json-schema.org/draft/2020-12/schema",
+				"type": "object",
+				"properties": {
+					"users": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"properties": {
+								"email": {"type": "string"},
+								"id": {"type": "integer"},
+								"name": {"type": "string"}
+							},
+							"required": ["id"]
+						}
+					}
+				},
+				"required": ["users"]
+			}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			samples := make([]Object, len(tc.samples))
+			for i, s := range tc.samples {
+				obj, err := Unmarshal([]byte(s))
+				if err != nil {
+					t.Fatalf("failed to parse sample: %v", err)
+				}
+				samples[i] = obj
+			}
+
+			expected, err := Unmarshal([]byte(tc.expected))
+			if err != nil {
+				t.Fatalf("failed to parse expected schema: %v", err)
+			}
+
+			actual := InferSchema(samples...)
+			if err := diff.JSON(map[string]interface{}(expected), map[string]interface{}(actual)); err != nil {
+				t.Errorf("inferred schema differs from expected:\n%v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package jsont
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrMalformedJWT indicates a token isn't three base64url encoded parts,
+	// or its header or claims aren't JSON objects.
+	ErrMalformedJWT = errors.New("malformed JWT")
+
+	// ErrInvalidSignature indicates a token's signature doesn't match the
+	// key it was verified with.
+	ErrInvalidSignature = errors.New("invalid JWT signature")
+)
+
+// JWT is a decoded JSON Web Token. Decoding doesn't verify the signature;
+// call Verify for that.
+type JWT struct {
+	Header    Object
+	Claims    Object
+	Signature []byte
+
+	signingInput string
+}
+
+// DecodeJWT decodes a compact JWT, "header.claims.signature", so its
+// header and claims can be queried with the usual getters:
+//
+//	tok, err := jsont.DecodeJWT(raw)
+//	...
+//	sub, err := tok.Claims.GetStr("sub")
+func DecodeJWT(token string) (*JWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.Wrapf(ErrMalformedJWT, "wanted 3 parts; got %d", len(parts))
+	}
+
+	header, err := decodeJWTPart(parts[0])
+	if err != nil {
+		return nil, errors.Wrapf(err, "header")
+	}
+
+	claims, err := decodeJWTPart(parts[1])
+	if err != nil {
+		return nil, errors.Wrapf(err, "claims")
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[2], "="))
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedJWT, "signature is not base64url: %v", err)
+	}
+
+	return &JWT{
+		Header:       header,
+		Claims:       claims,
+		Signature:    sig,
+		signingInput: parts[0] + "." + parts[1],
+	}, nil
+}
+
+func decodeJWTPart(part string) (Object, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedJWT, "not base64url: %v", err)
+	}
+
+	obj, err := Unmarshal(b)
+	if err != nil {
+		return nil, errors.Wrapf(ErrMalformedJWT, "not a JSON object: %v", err)
+	}
+
+	return obj, nil
+}
+
+// Verify checks the signature with a local key. The algorithm comes from
+// the header and must match the key: HS256 takes a []byte secret, RS256 an
+// *rsa.PublicKey and ES256 an *ecdsa.PublicKey on the P-256 curve. Other
+// algorithms, including "none", are rejected.
+func (j *JWT) Verify(key interface{}) error {
+	alg, err := j.Header.GetStr("alg")
+	if err != nil {
+		return errors.Wrap(err, "alg")
+	}
+
+	digest := sha256.Sum256([]byte(j.signingInput))
+
+	switch alg {
+	case "HS256":
+		secret, ok := key.([]byte)
+		if !ok {
+			return errors.Errorf("HS256 needs a []byte key; got %T", key)
+		}
+
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(j.signingInput))
+		if !hmac.Equal(mac.Sum(nil), j.Signature) {
+			return ErrInvalidSignature
+		}
+	case "RS256":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return errors.Errorf("RS256 needs an *rsa.PublicKey; got %T", key)
+		}
+
+		if err := rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], j.Signature); err != nil {
+			return ErrInvalidSignature
+		}
+	case "ES256":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return errors.Errorf("ES256 needs an *ecdsa.PublicKey; got %T", key)
+		}
+
+		if pub.Curve != elliptic.P256() {
+			return errors.Errorf("ES256 needs a P-256 key; got %s", pub.Curve.Params().Name)
+		}
+
+		// the signature is r and s, 32 bytes each
+		if len(j.Signature) != 64 {
+			return ErrInvalidSignature
+		}
+
+		r := new(big.Int).SetBytes(j.Signature[:32])
+		s := new(big.Int).SetBytes(j.Signature[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+	default:
+		return errors.Errorf("unsupported JWT algorithm %q", alg)
+	}
+
+	return nil
+}
+
+// ClaimOption configures CheckTimes
+type ClaimOption func(*claimConfig)
+
+type claimConfig struct {
+	now    func() time.Time
+	leeway time.Duration
+}
+
+// Clock sets the function CheckTimes gets the current time from. It
+// defaults to time.Now; tests can pin it to a fixed time.
+func Clock(now func() time.Time) ClaimOption {
+	return func(c *claimConfig) {
+		c.now = now
+	}
+}
+
+// Leeway allows for clock skew between whoever issued the token and the
+// clock it's checked with
+func Leeway(d time.Duration) ClaimOption {
+	return func(c *claimConfig) {
+		c.leeway = d
+	}
+}
+
+// CheckTimes checks the exp, nbf and iat claims against the current time:
+// the token must not have expired, must already be valid and must not
+// have been issued in the future. Claims that aren't set aren't checked.
+func (j *JWT) CheckTimes(opts ...ClaimOption) error {
+	cfg := claimConfig{now: time.Now}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	now := cfg.now()
+	var failures []string
+
+	if exp, ok, err := j.claimTime("exp"); err != nil {
+		failures = append(failures, err.Error())
+	} else if ok && !now.Before(exp.Add(cfg.leeway)) {
+		failures = append(failures, fmt.Sprintf("exp: token expired at %s", exp.Format(time.RFC3339)))
+	}
+
+	if nbf, ok, err := j.claimTime("nbf"); err != nil {
+		failures = append(failures, err.Error())
+	} else if ok && now.Add(cfg.leeway).Before(nbf) {
+		failures = append(failures, fmt.Sprintf("nbf: token is not valid until %s", nbf.Format(time.RFC3339)))
+	}
+
+	if iat, ok, err := j.claimTime("iat"); err != nil {
+		failures = append(failures, err.Error())
+	} else if ok && now.Add(cfg.leeway).Before(iat) {
+		failures = append(failures, fmt.Sprintf("iat: token was issued in the future, at %s", iat.Format(time.RFC3339)))
+	}
+
+	return joinFailures(failures)
+}
+
+// ExpiresAt returns the exp claim
+func (j *JWT) ExpiresAt() (time.Time, error) {
+	return j.requiredClaimTime("exp")
+}
+
+// NotBefore returns the nbf claim
+func (j *JWT) NotBefore() (time.Time, error) {
+	return j.requiredClaimTime("nbf")
+}
+
+// IssuedAt returns the iat claim
+func (j *JWT) IssuedAt() (time.Time, error) {
+	return j.requiredClaimTime("iat")
+}
+
+func (j *JWT) requiredClaimTime(claim string) (time.Time, error) {
+	t, ok, err := j.claimTime(claim)
+	if err == nil && !ok {
+		err = errors.Wrap(ErrPropertyDoesNotExist, claim)
+	}
+
+	return t, err
+}
+
+// claimTime reads a NumericDate claim: seconds since the epoch, which may
+// have a fraction
+func (j *JWT) claimTime(claim string) (time.Time, bool, error) {
+	if _, ok := j.Claims[claim]; !ok {
+		return time.Time{}, false, nil
+	}
+
+	secs, err := j.Claims.GetNumber(claim)
+	if err != nil {
+		return time.Time{}, false, errors.Wrap(err, claim)
+	}
+
+	whole, frac := math.Modf(secs)
+	return time.Unix(int64(whole), int64(frac*1e9)).UTC(), true, nil
+}
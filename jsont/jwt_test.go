@@ -0,0 +1,261 @@
+package jsont
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+// signJWT builds a token for the tests, signing it with key
+func signJWT(t *testing.T, alg, claims string, key interface{}) string {
+	t.Helper()
+
+	enc := base64.RawURLEncoding
+	input := enc.EncodeToString([]byte(`{"alg":"`+alg+`","typ":"JWT"}`)) + "." + enc.EncodeToString([]byte(claims))
+	digest := sha256.Sum256([]byte(input))
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(input))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		var err error
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return input + "." + enc.EncodeToString(sig)
+}
+
+func TestDecodeJWT(tt *testing.T) {
+	testcases := []struct {
+		name  string
+		token string
+		sub   string
+		err   error
+	}{
+		{
+			name:  "decodes the header and claims",
+			token: "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJib2IifQ.c2ln",
+			sub:   "bob",
+		},
+		{
+			name:  "rejects tokens without three parts",
+			token: "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJib2IifQ",
+			err:   errors.New("wanted 3 parts; got 2: malformed JWT"),
+		},
+		{
+			name:  "rejects claims that aren't JSON objects",
+			token: "eyJhbGciOiJIUzI1NiJ9.WzFd.c2ln",
+			err:   errors.New("claims: not a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}: malformed JWT"),
+		},
+		{
+			name:  "rejects parts that aren't base64url",
+			token: "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJib2IifQ.c2l*",
+			err:   errors.New("signature is not base64url: illegal base64 data at input byte 3: malformed JWT"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			tok, err := DecodeJWT(tc.token)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err != nil {
+				return
+			}
+
+			if sub, err := tok.Claims.GetStr("sub"); err != nil || sub != tc.sub {
+				t.Errorf("expected sub %q, got %q (%v)", tc.sub, sub, err)
+			}
+
+			if alg, _ := tok.Header.GetStr("alg"); alg != "HS256" {
+				t.Errorf("expected alg HS256, got %q", alg)
+			}
+		})
+	}
+}
+
+func TestJWT_Verify(tt *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	otherEC, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		tt.Fatal(err)
+	}
+
+	const claims = `{"sub":"bob"}`
+
+	testcases := []struct {
+		name  string
+		token string
+		key   interface{}
+		err   error
+	}{
+		{
+			name:  "verifies HS256",
+			token: signJWT(tt, "HS256", claims, []byte("secret")),
+			key:   []byte("secret"),
+		},
+		{
+			name:  "rejects HS256 signed with another secret",
+			token: signJWT(tt, "HS256", claims, []byte("secret")),
+			key:   []byte("guess"),
+			err:   ErrInvalidSignature,
+		},
+		{
+			name:  "verifies RS256",
+			token: signJWT(tt, "RS256", claims, rsaKey),
+			key:   &rsaKey.PublicKey,
+		},
+		{
+			name:  "verifies ES256",
+			token: signJWT(tt, "ES256", claims, ecKey),
+			key:   &ecKey.PublicKey,
+		},
+		{
+			name:  "rejects ES256 signed with another key",
+			token: signJWT(tt, "ES256", claims, otherEC),
+			key:   &ecKey.PublicKey,
+			err:   ErrInvalidSignature,
+		},
+		{
+			name:  "rejects ES256 keys on other curves",
+			token: signJWT(tt, "ES256", claims, ecKey),
+			key:   &p384.PublicKey,
+			err:   errors.New("ES256 needs a P-256 key; got P-384"),
+		},
+		{
+			name:  "rejects keys that don't match the algorithm",
+			token: signJWT(tt, "RS256", claims, rsaKey),
+			key:   []byte("secret"),
+			err:   errors.New("RS256 needs an *rsa.PublicKey; got []uint8"),
+		},
+		{
+			name:  "rejects unsigned tokens",
+			token: signJWT(tt, "none", claims, nil),
+			key:   []byte("secret"),
+			err:   errors.New(`unsupported JWT algorithm "none"`),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			tok, err := DecodeJWT(tc.token)
+			if err != nil {
+				t.Fatalf("failed to decode token: %v", err)
+			}
+
+			if testErr := compare.Errors(tc.err, tok.Verify(tc.key)); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
+
+func TestJWT_CheckTimes(tt *testing.T) {
+	now := time.Date(2021, 1, 30, 12, 0, 0, 0, time.UTC)
+	clock := Clock(func() time.Time { return now })
+
+	testcases := []struct {
+		name   string
+		claims string
+		opts   []ClaimOption
+		err    error
+	}{
+		{
+			name:   "passes a token that's valid now",
+			claims: `{"iat": 1612000000, "nbf": 1612000000, "exp": 1612015200}`,
+			opts:   []ClaimOption{clock},
+		},
+		{
+			name:   "passes a token without time claims",
+			claims: `{"sub": "bob"}`,
+			opts:   []ClaimOption{clock},
+		},
+		{
+			name:   "reports expired and not yet valid tokens",
+			claims: `{"iat": 1612022400, "nbf": 1612022400, "exp": 1612008000}`,
+			opts:   []ClaimOption{clock},
+			err: errors.New(`exp: token expired at 2021-01-30T12:00:00Z
+nbf: token is not valid until 2021-01-30T16:00:00Z
+iat: token was issued in the future, at 2021-01-30T16:00:00Z`),
+		},
+		{
+			name:   "allows for leeway",
+			claims: `{"nbf": 1612008030, "exp": 1612007970}`,
+			opts:   []ClaimOption{clock, Leeway(time.Minute)},
+		},
+		{
+			name:   "reports claims that aren't numbers",
+			claims: `{"exp": "tomorrow"}`,
+			opts:   []ClaimOption{clock},
+			err:    errors.New("exp: attempted to type string as float64"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			tok, err := DecodeJWT(signJWT(t, "HS256", tc.claims, []byte("secret")))
+			if err != nil {
+				t.Fatalf("failed to decode token: %v", err)
+			}
+
+			if testErr := compare.Errors(tc.err, tok.CheckTimes(tc.opts...)); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+
+	tt.Run("reads time claims", func(t *testing.T) {
+		tok, err := DecodeJWT(signJWT(t, "HS256", `{"exp": 1612008000.5}`, []byte("secret")))
+		if err != nil {
+			t.Fatalf("failed to decode token: %v", err)
+		}
+
+		if exp, err := tok.ExpiresAt(); err != nil || !exp.Equal(now.Add(500*time.Millisecond)) {
+			t.Errorf("expected exp %v, got %v (%v)", now.Add(500*time.Millisecond), exp, err)
+		}
+
+		if _, err := tok.IssuedAt(); !errors.Is(err, ErrPropertyDoesNotExist) {
+			t.Errorf("expected a missing iat claim, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,109 @@
+// Package leak scans JSON payloads for data that shouldn't be in them,
+// like passwords, credit card numbers and access keys, so tests can show
+// an API response doesn't leak it.
+//
+//	if err := leak.Scan(body); err != nil {
+//		t.Errorf("response leaks sensitive data:\n%v", err)
+//	}
+package leak
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+// Rule flags values that look sensitive. Check is given each value in the
+// payload along with its path, and returns a reason when it flags one.
+type Rule struct {
+	Name  string
+	Check func(p jsont.Path, v interface{}) (reason string, found bool)
+}
+
+// Finding is a value a Rule flagged
+type Finding struct {
+	Path   string
+	Rule   string
+	Reason string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s (%s)", f.Path, f.Reason, f.Rule)
+}
+
+// Findings is every Finding in a payload
+type Findings []Finding
+
+func (f Findings) Error() string {
+	lines := make([]string, len(f))
+	for i, finding := range f {
+		lines[i] = finding.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Scanner checks payloads against a set of Rules
+type Scanner struct {
+	rules   []Rule
+	allowed []jsont.Pattern
+}
+
+// NewScanner returns a Scanner with rules, or DefaultRules when none are
+// given
+func NewScanner(rules ...Rule) *Scanner {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+
+	return &Scanner{rules: rules}
+}
+
+// Allow skips the values at patterns, like "user.email", and everything
+// under them. Patterns take the wildcards a jsont.Normalizer does, so
+// "users[*].email" allows every user's email. Use it for fields that are
+// meant to be there.
+func (s *Scanner) Allow(patterns ...string) *Scanner {
+	for _, p := range patterns {
+		s.allowed = append(s.allowed, jsont.CompilePattern(p))
+	}
+
+	return s
+}
+
+// Scan checks every value in obj and returns the Findings, or nil if there
+// are none. Each value is reported once, by the first Rule that flags it.
+func (s *Scanner) Scan(obj jsont.Object) error {
+	var findings Findings
+
+	obj.Walk(func(p jsont.Path, v interface{}) jsont.WalkAction {
+		for _, allowed := range s.allowed {
+			if allowed.Match(p) {
+				return jsont.SkipSubtree
+			}
+		}
+
+		path := p.String()
+
+		for _, r := range s.rules {
+			if reason, found := r.Check(p, v); found {
+				findings = append(findings, Finding{Path: path, Rule: r.Name, Reason: reason})
+				break
+			}
+		}
+
+		return jsont.Continue
+	})
+
+	if len(findings) > 0 {
+		return findings
+	}
+
+	return nil
+}
+
+// Scan checks obj with the DefaultRules
+func Scan(obj jsont.Object) error {
+	return NewScanner().Scan(obj)
+}
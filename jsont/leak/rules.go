@@ -0,0 +1,207 @@
+package leak
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dedwardstech/test/jsont"
+)
+
+// DefaultKeyNames are the key names DefaultRules flags
+var DefaultKeyNames = []string{
+	"password", "passwd", "secret", "token", "apikey", "privatekey",
+	"ssn", "socialsecurity", "creditcard", "cardnumber", "cvv",
+}
+
+// DefaultRules returns the rules a Scanner uses unless it's given others,
+// most specific first
+func DefaultRules() []Rule {
+	return []Rule{
+		KeyNames(DefaultKeyNames...),
+		JWTs(),
+		AWSKeys(),
+		CreditCards(),
+		Emails(),
+		HighEntropy(20, 4.0),
+	}
+}
+
+// KeyNames flags strings under keys containing any of names. Case,
+// underscores and dashes are ignored, so "password" matches "Password"
+// and "user_password". Only non-empty strings are flagged: flags like
+// "password_set": true, counts like "token_count": 3 and the objects
+// holding secrets, whose strings are checked on their own, are not.
+func KeyNames(names ...string) Rule {
+	return Rule{
+		Name: "key name",
+		Check: func(p jsont.Path, v interface{}) (string, bool) {
+			last := p.Last()
+			if s, ok := v.(string); last.IsIndex || !ok || s == "" {
+				return "", false
+			}
+
+			key := normalizeKey(last.Key)
+			for _, name := range names {
+				if strings.Contains(key, normalizeKey(name)) {
+					return "key looks like it holds a " + name, true
+				}
+			}
+
+			return "", false
+		},
+	}
+}
+
+func normalizeKey(k string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(k))
+}
+
+var (
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+	awsKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)
+	cardPattern   = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// JWTs flags strings holding a JSON Web Token
+func JWTs() Rule {
+	return patternRule("jwt", jwtPattern, "looks like a JWT")
+}
+
+// AWSKeys flags strings holding an AWS access key ID
+func AWSKeys() Rule {
+	return patternRule("aws key", awsKeyPattern, "looks like an AWS access key ID")
+}
+
+// Emails flags strings holding an email address
+func Emails() Rule {
+	return patternRule("email", emailPattern, "looks like an email address")
+}
+
+func patternRule(name string, pattern *regexp.Regexp, reason string) Rule {
+	return Rule{
+		Name: name,
+		Check: func(p jsont.Path, v interface{}) (string, bool) {
+			s, ok := v.(string)
+			return reason, ok && pattern.MatchString(s)
+		},
+	}
+}
+
+// CreditCards flags strings holding 13 to 19 digits, optionally separated
+// by spaces or dashes, that pass the Luhn check. Whole numbers are checked
+// too, in case a card number was serialized as a JSON number, but only
+// under keys containing "card", like "card_number": one in ten IDs and
+// millisecond timestamps pass the Luhn check.
+func CreditCards() Rule {
+	return Rule{
+		Name: "credit card",
+		Check: func(p jsont.Path, v interface{}) (string, bool) {
+			s, ok := digits(v)
+			if !ok {
+				return "", false
+			}
+
+			if _, isString := v.(string); !isString && !isCardKey(p.Last()) {
+				return "", false
+			}
+
+			for _, candidate := range cardPattern.FindAllString(s, -1) {
+				if luhn(candidate) {
+					return "looks like a credit card number", true
+				}
+			}
+
+			return "", false
+		},
+	}
+}
+
+// isCardKey reports whether a number is under a key that names a card
+func isCardKey(last jsont.PathSegment) bool {
+	return !last.IsIndex && strings.Contains(normalizeKey(last.Key), "card")
+}
+
+// digits returns a string, or a whole number written out in full
+func digits(v interface{}) (string, bool) {
+	switch t := v.(type) {
+	case string:
+		return t, true
+	case json.Number:
+		return t.String(), true
+	case int:
+		return strconv.Itoa(t), true
+	case int64:
+		return strconv.FormatInt(t, 10), true
+	case float64:
+		if t < 0 || t != math.Trunc(t) {
+			return "", false
+		}
+
+		return strconv.FormatFloat(t, 'f', -1, 64), true
+	}
+
+	return "", false
+}
+
+// luhn reports whether the digits in s pass the Luhn checksum
+func luhn(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		d := int(c - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
+
+// HighEntropy flags strings of at least minLen characters, with no
+// whitespace, whose Shannon entropy is at least minBits per character.
+// Random keys and tokens score highly; words and UUIDs don't.
+func HighEntropy(minLen int, minBits float64) Rule {
+	return Rule{
+		Name: "high entropy",
+		Check: func(p jsont.Path, v interface{}) (string, bool) {
+			s, ok := v.(string)
+			if !ok || utf8.RuneCountInString(s) < minLen || strings.ContainsAny(s, " \t\r\n") {
+				return "", false
+			}
+
+			return "looks like a random secret", entropy(s) >= minBits
+		},
+	}
+}
+
+// entropy returns the Shannon entropy of s in bits per character
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+
+	var bits float64
+	for _, c := range counts {
+		f := float64(c) / float64(n)
+		bits -= f * math.Log2(f)
+	}
+
+	return bits
+}
@@ -0,0 +1,214 @@
+package jsont
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Line is one line of a JSON Lines (newline-delimited JSON) stream. Number
+// counts from 1 and Offset is the byte offset the line starts at. Lines
+// that aren't a JSON object have Err set instead of Object.
+type Line struct {
+	Number int
+	Offset int64
+	Object Object
+	Err    error
+}
+
+// LineReader reads a JSON Lines stream one line at a time. A malformed
+// line doesn't stop it; the line is returned with its Err set and reading
+// carries on with the next one.
+//
+//	lr := jsont.ReadLines(r)
+//	for lr.Next() {
+//		line := lr.Line()
+//		...
+//	}
+//	if err := lr.Err(); err != nil {
+//		...
+//	}
+type LineReader struct {
+	r      *bufio.Reader
+	line   Line
+	number int
+	offset int64
+	err    error
+}
+
+// ReadLines starts reading a JSON Lines stream
+func ReadLines(r io.Reader) *LineReader {
+	return &LineReader{r: bufio.NewReader(r)}
+}
+
+// Next advances to the next line that isn't blank. It returns false at the
+// end of the stream, or if reading fails.
+func (lr *LineReader) Next() bool {
+	for lr.err == nil {
+		raw, err := lr.r.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			lr.err = err
+			return false
+		}
+
+		if len(raw) == 0 && err == io.EOF {
+			return false
+		}
+
+		lr.number++
+		offset := lr.offset
+		lr.offset += int64(len(raw))
+
+		trimmed := bytes.TrimSpace(raw)
+		if len(trimmed) == 0 {
+			if err == io.EOF {
+				return false
+			}
+			continue
+		}
+
+		lr.line = Line{Number: lr.number, Offset: offset}
+		obj, err := Unmarshal(trimmed)
+		switch {
+		case err != nil:
+			lr.line.Err = errors.Wrapf(err, "line %d (offset %d) is not a JSON object", lr.number, offset)
+		case obj == nil:
+			// null unmarshals into a nil map without an error
+			lr.line.Err = errors.Errorf("line %d (offset %d) is not a JSON object: null", lr.number, offset)
+		default:
+			lr.line.Object = obj
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Line returns the line Next advanced to
+func (lr *LineReader) Line() Line {
+	return lr.line
+}
+
+// Err returns the error that stopped reading, if any. Malformed lines
+// aren't included; they're reported on each Line.
+func (lr *LineReader) Err() error {
+	return lr.err
+}
+
+// Lines is a whole JSON Lines stream, read into memory so assertions can
+// be made about it.
+type Lines []Line
+
+// ReadAllLines reads every line of a JSON Lines stream
+func ReadAllLines(r io.Reader) (Lines, error) {
+	var lines Lines
+
+	lr := ReadLines(r)
+	for lr.Next() {
+		lines = append(lines, lr.Line())
+	}
+
+	return lines, lr.Err()
+}
+
+// Objects returns the Objects of every line that parsed
+func (ls Lines) Objects() []Object {
+	objs := make([]Object, 0, len(ls))
+	for _, l := range ls {
+		if l.Err == nil {
+			objs = append(objs, l.Object)
+		}
+	}
+
+	return objs
+}
+
+// Malformed returns the lines that couldn't be parsed
+func (ls Lines) Malformed() Lines {
+	var malformed Lines
+	for _, l := range ls {
+		if l.Err != nil {
+			malformed = append(malformed, l)
+		}
+	}
+
+	return malformed
+}
+
+// Count checks the stream holds n records. Malformed lines aren't records,
+// but they are reported.
+func (ls Lines) Count(n int) error {
+	failures := ls.malformedFailures()
+
+	if count := len(ls.Objects()); count != n {
+		failures = append([]string{fmt.Sprintf("wanted %d records; got %d", n, count)}, failures...)
+	}
+
+	return joinFailures(failures)
+}
+
+// AllContain checks every line contains the template, the same way
+// Contains does. Malformed lines fail the check.
+func (ls Lines) AllContain(template Object, opts ...MatchOption) error {
+	var failures []string
+
+	for _, l := range ls {
+		if l.Err != nil {
+			failures = append(failures, l.Err.Error())
+			continue
+		}
+
+		if err := Contains(l.Object, template, opts...); err != nil {
+			for _, m := range err.(MismatchError) {
+				failures = append(failures, fmt.Sprintf("line %d: %s: %s", l.Number, m.Path, m.Reason))
+			}
+		}
+	}
+
+	return joinFailures(failures)
+}
+
+// ContainInOrder checks the stream has a line containing each record, in
+// the order given. Other lines can come before, after or between them.
+func (ls Lines) ContainInOrder(records []Object, opts ...MatchOption) error {
+	next := 0
+
+	for _, l := range ls {
+		if next == len(records) {
+			break
+		}
+
+		if l.Err == nil && Contains(l.Object, records[next], opts...) == nil {
+			next++
+		}
+	}
+
+	failures := ls.malformedFailures()
+	if next < len(records) {
+		failures = append([]string{fmt.Sprintf("record %d was not found in order: %v", next, records[next])}, failures...)
+	}
+
+	return joinFailures(failures)
+}
+
+func (ls Lines) malformedFailures() []string {
+	var failures []string
+	for _, l := range ls.Malformed() {
+		failures = append(failures, l.Err.Error())
+	}
+
+	return failures
+}
+
+func joinFailures(failures []string) error {
+	if len(failures) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(failures, "\n"))
+}
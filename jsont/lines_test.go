@@ -0,0 +1,115 @@
+package jsont
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+const logStream = `{"level": "info", "msg": "start", "id": 1}
+{"level": "info", "msg": "request", "id": 2}
+
+{"level": "warn", "msg": "slow", "id": 3}
+{"level": "info", "msg": "done", "id": 4}`
+
+func TestReadLines(t *testing.T) {
+	lines, err := ReadAllLines(strings.NewReader("{\"a\": 1}\r\nnot json\n\n[1]\n{\"b\": 2}\nnull"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		number int
+		offset int64
+		err    error
+	}{
+		{number: 1, offset: 0},
+		{number: 2, offset: 10, err: errors.New("line 2 (offset 10) is not a JSON object: invalid character 'o' in literal null (expecting 'u')")},
+		{number: 4, offset: 20, err: errors.New("line 4 (offset 20) is not a JSON object: json: cannot unmarshal array into Go value of type map[string]interface {}")},
+		{number: 5, offset: 24},
+		{number: 6, offset: 33, err: errors.New("line 6 (offset 33) is not a JSON object: null")},
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d", len(expected), len(lines))
+	}
+
+	for i, e := range expected {
+		l := lines[i]
+		if l.Number != e.number || l.Offset != e.offset {
+			t.Errorf("line %d: expected number %d at offset %d, got %d at %d", i, e.number, e.offset, l.Number, l.Offset)
+		}
+
+		if testErr := compare.Errors(e.err, l.Err); testErr != nil {
+			t.Errorf("line %d: %v", i, testErr)
+		}
+	}
+}
+
+func TestLines(tt *testing.T) {
+	lines, err := ReadAllLines(strings.NewReader(logStream))
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+
+	withMalformed, err := ReadAllLines(strings.NewReader(logStream + "\n{oops"))
+	if err != nil {
+		tt.Fatalf("unexpected error: %v", err)
+	}
+
+	testcases := []struct {
+		name  string
+		check func() error
+		err   error
+	}{
+		{
+			name:  "counts records",
+			check: func() error { return lines.Count(4) },
+			err:   nil,
+		},
+		{
+			name:  "reports the wrong number of records",
+			check: func() error { return lines.Count(5) },
+			err:   errors.New("wanted 5 records; got 4"),
+		},
+		{
+			name:  "checks every line matches a template",
+			check: func() error { return lines.AllContain(Object{"id": AnyNumber(), "msg": AnyString()}) },
+			err:   nil,
+		},
+		{
+			name:  "reports lines that don't match a template",
+			check: func() error { return lines.AllContain(Object{"level": "info"}) },
+			err:   errors.New(`line 4: level: wanted "info"; got "warn"`),
+		},
+		{
+			name: "finds records in order",
+			check: func() error {
+				return lines.ContainInOrder([]Object{{"msg": "start"}, {"level": "warn"}, {"id": 4}})
+			},
+			err: nil,
+		},
+		{
+			name: "reports records out of order",
+			check: func() error {
+				return lines.ContainInOrder([]Object{{"msg": "slow"}, {"msg": "request"}})
+			},
+			err: errors.New("record 1 was not found in order: map[msg:request]"),
+		},
+		{
+			name:  "reports malformed lines with their offsets",
+			check: func() error { return withMalformed.Count(4) },
+			err:   errors.New("line 6 (offset 173) is not a JSON object: invalid character 'o' looking for beginning of object key string"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			if testErr := compare.Errors(tc.err, tc.check()); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package jsont
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/dedwardstech/test/internal/number"
+	"github.com/dedwardstech/test/internal/path"
+)
+
+// ArrayMode controls how arrays in an expected Object are matched
+// against arrays in the actual Object.
+type ArrayMode int
+
+const (
+	// ArraysEqual requires arrays to have the same elements in the same
+	// order. Objects inside them must match exactly, not as a subset.
+	ArraysEqual ArrayMode = iota
+
+	// ArraysOrdered requires every expected element to match an actual
+	// element, in the same order. The actual array may hold extra elements
+	// before, after or between them.
+	ArraysOrdered
+
+	// ArraysUnordered requires every expected element to match a different
+	// actual element, in any order.
+	ArraysUnordered
+)
+
+// MatchOption configures how Contains and Match compare two Objects
+type MatchOption func(*matchConfig)
+
+type matchConfig struct {
+	arrays     ArrayMode
+	normalizer *Normalizer
+
+	// set by the options in equal.go
+	strictNumbers     bool
+	nullEqualsMissing bool
+	extraKeysInA      bool
+	extraKeysInB      bool
+	unorderedAt       [][]patternSegment
+	trimStrings       bool
+}
+
+// Arrays sets how arrays are matched. By default arrays must be equal.
+func Arrays(mode ArrayMode) MatchOption {
+	return func(c *matchConfig) {
+		c.arrays = mode
+	}
+}
+
+// Mismatch describes one place where an actual value didn't match the
+// expected one.
+type Mismatch struct {
+	Path   string
+	Reason string
+}
+
+// MismatchError is every Mismatch found when comparing two Objects
+type MismatchError []Mismatch
+
+func (e MismatchError) Error() string {
+	lines := make([]string, len(e))
+	for i, m := range e {
+		lines[i] = fmt.Sprintf("%s: %s", m.Path, m.Reason)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Contains checks that every key in expected exists in actual with an equal
+// value. Nested objects are matched the same way, so only the keys you care
+// about need to be listed, however deep they are.
+//
+//	actual := {"id": 1, "user": {"name": "bob", "age": 42}}
+//
+//	Contains(actual, {"user": {"name": "bob"}}) => nil
+//	Contains(actual, {"user": {"name": "al"}}) => user.name: wanted "al"; got "bob"
+//
+// Arrays must be equal unless an ArrayMode says otherwise. Any value in
+// expected can be replaced by a Matcher.
+func Contains(actual, expected Object, opts ...MatchOption) error {
+	return newMatcher(opts).compare(actual, expected, true)
+}
+
+// Match checks that actual is the same as expected, apart from the values
+// that expected replaces with a Matcher. Unlike Contains, keys in actual
+// that aren't in expected are reported.
+//
+//	Match(body, Object{"id": UUID(), "name": "bob", "created": Ignore()})
+func Match(actual, expected Object, opts ...MatchOption) error {
+	return newMatcher(opts).compare(actual, expected, false)
+}
+
+type matcher struct {
+	cfg matchConfig
+}
+
+func newMatcher(opts []MatchOption) *matcher {
+	m := &matcher{}
+	for _, opt := range opts {
+		opt(&m.cfg)
+	}
+
+	return m
+}
+
+func (m *matcher) compare(actual, expected Object, subset bool) error {
+	if n := m.cfg.normalizer; n != nil {
+		actual, expected = n.Normalize(actual), n.Normalize(expected)
+	}
+
+	mismatches := m.match("", map[string]interface{}(expected), map[string]interface{}(actual), subset)
+	if len(mismatches) > 0 {
+		return MismatchError(mismatches)
+	}
+
+	return nil
+}
+
+// matches reports whether actual is equal to expected, honoring any
+// Matchers in expected.
+func matches(expected, actual interface{}) bool {
+	return len(newMatcher(nil).match("", expected, actual, false)) == 0
+}
+
+// match compares expected to actual. When subset is true, objects in actual
+// may have keys that aren't in expected.
+func (m *matcher) match(p string, expected, actual interface{}, subset bool) []Mismatch {
+	if vm, ok := expected.(Matcher); ok {
+		if err := vm.Match(actual); err != nil {
+			return mismatch(p, "%v", err)
+		}
+
+		return nil
+	}
+
+	if em, ok := asMap(expected); ok {
+		am, ok := asMap(actual)
+		if !ok {
+			return mismatch(p, "wanted an object; got %s", describe(actual))
+		}
+
+		return m.matchObject(p, em, am, subset)
+	}
+
+	if es, ok := expected.([]interface{}); ok {
+		as, ok := actual.([]interface{})
+		if !ok {
+			return mismatch(p, "wanted an array; got %s", describe(actual))
+		}
+
+		return m.matchArray(p, es, as, subset)
+	}
+
+	if es, ok := expected.(string); ok && m.cfg.trimStrings {
+		if as, ok := actual.(string); ok && strings.TrimSpace(es) == strings.TrimSpace(as) {
+			return nil
+		}
+	} else if x, ok := number.Float64(expected); ok && !m.cfg.strictNumbers {
+		if y, ok := number.Float64(actual); ok && x == y {
+			return nil
+		}
+	} else if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+
+	if x, ok := number.Float64(expected); ok && m.cfg.strictNumbers {
+		if y, ok := number.Float64(actual); ok && x == y {
+			return mismatch(p, "wanted %T %s; got %T %s", expected, describe(expected), actual, describe(actual))
+		}
+	}
+
+	return mismatch(p, "wanted %s; got %s", describe(expected), describe(actual))
+}
+
+func (m *matcher) matchObject(p string, expected, actual map[string]interface{}, subset bool) []Mismatch {
+	var mismatches []Mismatch
+
+	for _, key := range sortedKeys(expected) {
+		keyPath := path.Key(p, key)
+
+		av, ok := actual[key]
+		if _, ignored := expected[key].(ignoreMatcher); ignored {
+			continue
+		}
+
+		if !ok {
+			if !m.cfg.extraKeysInB && !(m.cfg.nullEqualsMissing && expected[key] == nil) {
+				mismatches = append(mismatches, Mismatch{Path: keyPath, Reason: ErrPropertyDoesNotExist.Error()})
+			}
+			continue
+		}
+
+		mismatches = append(mismatches, m.match(keyPath, expected[key], av, subset)...)
+	}
+
+	if subset || m.cfg.extraKeysInA {
+		return mismatches
+	}
+
+	for _, key := range sortedKeys(actual) {
+		if _, ok := expected[key]; !ok && !(m.cfg.nullEqualsMissing && actual[key] == nil) {
+			mismatches = append(mismatches, Mismatch{Path: path.Key(p, key), Reason: "unexpected key"})
+		}
+	}
+
+	return mismatches
+}
+
+func (m *matcher) matchArray(p string, expected, actual []interface{}, subset bool) []Mismatch {
+	mode := m.cfg.arrays
+	if m.unordered(p) {
+		mode = ArraysUnordered
+	}
+
+	if mode == ArraysEqual {
+		subset = false
+	}
+
+	if !subset && len(expected) != len(actual) {
+		return mismatch(p, "wanted %d elements; got %d", len(expected), len(actual))
+	}
+
+	switch {
+	case mode == ArraysUnordered:
+		return m.matchUnordered(p, expected, actual, subset)
+	case subset:
+		return m.matchSubsequence(p, expected, actual)
+	}
+
+	var mismatches []Mismatch
+	for i := range expected {
+		mismatches = append(mismatches, m.match(path.Index(p, i), expected[i], actual[i], subset)...)
+	}
+
+	return mismatches
+}
+
+// matchSubsequence finds the expected elements in actual, in order. Taking
+// the earliest actual element that matches never rules out a later match.
+func (m *matcher) matchSubsequence(p string, expected, actual []interface{}) []Mismatch {
+	j := 0
+	for i, ev := range expected {
+		for j < len(actual) && len(m.match(path.Index(p, j), ev, actual[j], true)) > 0 {
+			j++
+		}
+
+		if j == len(actual) {
+			return mismatch(path.Index(p, i), "no matching element in order; wanted %s", describe(ev))
+		}
+
+		j++
+	}
+
+	return nil
+}
+
+// matchUnordered pairs every expected element with a different actual
+// element. It's a bipartite matching, since the first element that matches
+// may be the only match for a later expected element.
+func (m *matcher) matchUnordered(p string, expected, actual []interface{}, subset bool) []Mismatch {
+	candidates := make([][]int, len(expected))
+	for i, ev := range expected {
+		for j, av := range actual {
+			if len(m.match(path.Index(p, j), ev, av, subset)) == 0 {
+				candidates[i] = append(candidates[i], j)
+			}
+		}
+	}
+
+	owner := make([]int, len(actual))
+	for j := range owner {
+		owner[j] = -1
+	}
+
+	var assign func(i int, seen []bool) bool
+	assign = func(i int, seen []bool) bool {
+		for _, j := range candidates[i] {
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+
+			if owner[j] == -1 || assign(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var mismatches []Mismatch
+	for i, ev := range expected {
+		if !assign(i, make([]bool, len(actual))) {
+			mismatches = append(mismatches, mismatch(path.Index(p, i), "no matching element; wanted %s", describe(ev))...)
+		}
+	}
+
+	return mismatches
+}
+
+func mismatch(p, format string, args ...interface{}) []Mismatch {
+	return []Mismatch{{Path: p, Reason: fmt.Sprintf(format, args...)}}
+}
+
+// describe formats a value for a Mismatch. Strings are quoted so "1" can
+// be told apart from 1.
+func describe(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+
+	if v == nil {
+		return "null"
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,172 @@
+package jsont
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestContains(tt *testing.T) {
+	actual := Object{
+		"id": float64(7),
+		"user": map[string]interface{}{
+			"name": "bob",
+			"age":  float64(42),
+		},
+		"tags": []interface{}{"a", "b", "c"},
+		"items": []interface{}{
+			map[string]interface{}{"sku": "x", "qty": float64(1)},
+			map[string]interface{}{"sku": "y", "qty": float64(2)},
+		},
+	}
+
+	testcases := []struct {
+		name     string
+		expected Object
+		opts     []MatchOption
+		err      error
+	}{
+		{
+			name:     "matches a subset of nested keys",
+			expected: Object{"user": map[string]interface{}{"name": "bob"}},
+			err:      nil,
+		},
+		{
+			name:     "compares numbers by value",
+			expected: Object{"id": 7, "user": Object{"age": int64(42)}},
+			err:      nil,
+		},
+		{
+			name:     "reports values that differ with their path",
+			expected: Object{"user": map[string]interface{}{"name": "al"}},
+			err:      errors.New(`user.name: wanted "al"; got "bob"`),
+		},
+		{
+			name:     "reports missing keys",
+			expected: Object{"user": map[string]interface{}{"email": "bob@example.com"}},
+			err:      errors.New("user.email: json path does not exist"),
+		},
+		{
+			name:     "reports type mismatches",
+			expected: Object{"user": "bob"},
+			err:      errors.New(`user: wanted "bob"; got map[age:42 name:bob]`),
+		},
+		{
+			name:     "requires arrays to be equal by default",
+			expected: Object{"tags": []interface{}{"a", "b"}},
+			err:      errors.New("tags: wanted 2 elements; got 3"),
+		},
+		{
+			name:     "requires objects in arrays to be equal by default",
+			expected: Object{"items": []interface{}{Object{"sku": "x"}, Object{"sku": "y"}}},
+			err:      errors.New("items[0].qty: unexpected key\nitems[1].qty: unexpected key"),
+		},
+		{
+			name:     "matches ordered subsets of arrays",
+			expected: Object{"tags": []interface{}{"a", "c"}},
+			opts:     []MatchOption{Arrays(ArraysOrdered)},
+			err:      nil,
+		},
+		{
+			name:     "rejects ordered subsets in the wrong order",
+			expected: Object{"tags": []interface{}{"c", "a"}},
+			opts:     []MatchOption{Arrays(ArraysOrdered)},
+			err:      errors.New(`tags[1]: no matching element in order; wanted "a"`),
+		},
+		{
+			name:     "matches unordered subsets of arrays",
+			expected: Object{"tags": []interface{}{"c", "a"}},
+			opts:     []MatchOption{Arrays(ArraysUnordered)},
+			err:      nil,
+		},
+		{
+			name:     "recurses into objects inside arrays",
+			expected: Object{"items": []interface{}{Object{"sku": "y"}}},
+			opts:     []MatchOption{Arrays(ArraysUnordered)},
+			err:      nil,
+		},
+		{
+			name:     "matches each unordered element once",
+			expected: Object{"tags": []interface{}{"a", "a"}},
+			opts:     []MatchOption{Arrays(ArraysUnordered)},
+			err:      errors.New(`tags[1]: no matching element; wanted "a"`),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := Contains(actual, tc.expected, tc.opts...)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
+
+func TestMatch(tt *testing.T) {
+	actual := Object{
+		"id":      "usr_9f2c",
+		"uuid":    "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10",
+		"created": "2021-01-30T12:00:00.123Z",
+		"score":   float64(7),
+		"name":    "bob",
+		"etag":    "W/123",
+	}
+
+	testcases := []struct {
+		name     string
+		expected Object
+		err      error
+	}{
+		{
+			name: "accepts values that satisfy their matchers",
+			expected: Object{
+				"id":      Regexp(`^usr_`),
+				"uuid":    UUID(),
+				"created": RFC3339(),
+				"score":   Between(1, 10),
+				"name":    AnyString(),
+				"etag":    Ignore(),
+			},
+			err: nil,
+		},
+		{
+			name: "lets ignored keys be missing",
+			expected: Object{
+				"id": AnyString(), "uuid": AnyString(), "created": AnyString(),
+				"score": AnyNumber(), "name": "bob", "etag": Ignore(), "deleted": Ignore(),
+			},
+			err: nil,
+		},
+		{
+			name: "reports values rejected by matchers",
+			expected: Object{
+				"id":      UUID(),
+				"uuid":    AnyNumber(),
+				"created": RFC3339(),
+				"score":   Between(8, 10),
+				"name":    Regexp(`^al`),
+				"etag":    Ignore(),
+			},
+			err: errors.New(`id: wanted a UUID; got "usr_9f2c"
+name: wanted a string matching ^al; got "bob"
+score: wanted a number between 8 and 10; got 7
+uuid: wanted a number; got "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10"`),
+		},
+		{
+			name:     "reports keys missing from the expected Object",
+			expected: Object{"id": AnyString(), "uuid": UUID(), "created": RFC3339(), "score": 7},
+			err:      errors.New("etag: unexpected key\nname: unexpected key"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := Match(actual, tc.expected)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package jsont
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// Matcher can stand in for a value in an expected Object. Instead of
+// being compared for equality, it's asked whether the actual value is
+// acceptable. Use it for values that change on every run, like IDs and
+// timestamps.
+//
+//	Contains(body, Object{"id": UUID(), "created": RFC3339()})
+type Matcher interface {
+	Match(actual interface{}) error
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// valueMatcher is a Matcher described by what it wants, so its failures
+// read the same as every other Mismatch.
+type valueMatcher struct {
+	wanted string
+	accept func(v interface{}) bool
+}
+
+func (m valueMatcher) Match(actual interface{}) error {
+	if !m.accept(actual) {
+		return fmt.Errorf("wanted %s; got %s", m.wanted, describe(actual))
+	}
+
+	return nil
+}
+
+func (m valueMatcher) String() string {
+	return "<" + m.wanted + ">"
+}
+
+// ignoreMatcher accepts anything, including a missing key
+type ignoreMatcher struct{}
+
+func (ignoreMatcher) Match(interface{}) error {
+	return nil
+}
+
+func (ignoreMatcher) String() string {
+	return "<ignored>"
+}
+
+// AnyString matches any string value
+func AnyString() Matcher {
+	return valueMatcher{
+		wanted: "a string",
+		accept: func(v interface{}) bool {
+			_, ok := v.(string)
+			return ok
+		},
+	}
+}
+
+// AnyNumber matches any number value
+func AnyNumber() Matcher {
+	return valueMatcher{
+		wanted: "a number",
+		accept: func(v interface{}) bool {
+			_, ok := number.Float64(v)
+			return ok
+		},
+	}
+}
+
+// Regexp matches strings that match the regular expression. Like
+// regexp.MustCompile, it panics if the expression doesn't compile.
+func Regexp(expr string) Matcher {
+	re := regexp.MustCompile(expr)
+
+	return valueMatcher{
+		wanted: "a string matching " + expr,
+		accept: func(v interface{}) bool {
+			s, ok := v.(string)
+			return ok && re.MatchString(s)
+		},
+	}
+}
+
+// UUID matches strings in the 8-4-4-4-12 hex format of a UUID
+func UUID() Matcher {
+	return valueMatcher{
+		wanted: "a UUID",
+		accept: func(v interface{}) bool {
+			s, ok := v.(string)
+			return ok && uuidPattern.MatchString(s)
+		},
+	}
+}
+
+// RFC3339 matches strings that are RFC 3339 timestamps, with or without
+// fractional seconds.
+func RFC3339() Matcher {
+	return valueMatcher{
+		wanted: "an RFC 3339 timestamp",
+		accept: func(v interface{}) bool {
+			s, ok := v.(string)
+			if !ok {
+				return false
+			}
+
+			_, err := time.Parse(time.RFC3339Nano, s)
+			return err == nil
+		},
+	}
+}
+
+// Between matches numbers from lo to hi, inclusive
+func Between(lo, hi float64) Matcher {
+	return valueMatcher{
+		wanted: fmt.Sprintf("a number between %v and %v", lo, hi),
+		accept: func(v interface{}) bool {
+			n, ok := number.Float64(v)
+			return ok && n >= lo && n <= hi
+		},
+	}
+}
+
+// Ignore matches anything. The key it's under doesn't even need to exist.
+func Ignore() Matcher {
+	return ignoreMatcher{}
+}
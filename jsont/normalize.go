@@ -0,0 +1,334 @@
+package jsont
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/dedwardstech/test/diff"
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// Normalizer rewrites the volatile parts of a payload, like IDs,
+// timestamps and arrays built from map iteration, into a stable form so
+// payloads can be compared, diffed and snapshotted without churn.
+//
+// Each rule applies to the values whose path matches a pattern. Patterns
+// are property paths, like "users[0].id", where "*" matches any key,
+// "[*]" any index and "**" any number of steps, including none. Keys are
+// quoted the way paths quote them, like `labels["app.id"]`, and a quoted
+// key, even `["*"]`, only matches itself:
+//
+//	n := jsont.NewNormalizer().
+//		Replace("**.id", "<uuid>").
+//		RoundFloats("prices[*].amount", 2).
+//		SortBy("users", "name").
+//		StripNulls("**")
+//
+// Values are normalized from the leaves up, so a rule on an array sees
+// its elements already normalized. Rules on the same value run in the
+// order they were added.
+type Normalizer struct {
+	rules []normalizeRule
+}
+
+type normalizeRule struct {
+	pattern []patternSegment
+	apply   func(v interface{}) (interface{}, bool)
+}
+
+// NewNormalizer returns a Normalizer with no rules
+func NewNormalizer() *Normalizer {
+	return &Normalizer{}
+}
+
+// Replace replaces the values at pattern with placeholder, like "<uuid>"
+func (n *Normalizer) Replace(pattern string, placeholder interface{}) *Normalizer {
+	return n.add(pattern, func(interface{}) (interface{}, bool) {
+		return placeholder, true
+	})
+}
+
+// RoundFloats rounds the numbers at pattern to places decimal places
+func (n *Normalizer) RoundFloats(pattern string, places int) *Normalizer {
+	scale := math.Pow(10, float64(places))
+
+	return n.add(pattern, func(v interface{}) (interface{}, bool) {
+		f, ok := number.Float64(v)
+		if !ok {
+			return v, true
+		}
+
+		return math.Round(f*scale) / scale, true
+	})
+}
+
+// SortBy sorts the arrays at pattern by the values at keys in each
+// element, property paths like "user.id". Elements equal on the first key
+// are sorted by the next, and so on; with no keys the elements themselves
+// are compared. Numbers sort by value and strings alphabetically; elements
+// missing a key sort before those that have it.
+//
+//	n.SortBy("users", "lastName", "firstName")
+func (n *Normalizer) SortBy(pattern string, keys ...string) *Normalizer {
+	return n.add(pattern, func(v interface{}) (interface{}, bool) {
+		a, ok := v.([]interface{})
+		if !ok {
+			return v, true
+		}
+
+		sort.SliceStable(a, func(i, j int) bool {
+			return compareElements(a[i], a[j], keys) < 0
+		})
+
+		return a, true
+	})
+}
+
+// StripNulls removes object members whose value is null at pattern. Use
+// "**" to strip them everywhere.
+func (n *Normalizer) StripNulls(pattern string) *Normalizer {
+	return n.add(pattern, func(v interface{}) (interface{}, bool) {
+		return v, v != nil
+	})
+}
+
+// Lowercase lowercases the strings at pattern
+func (n *Normalizer) Lowercase(pattern string) *Normalizer {
+	return n.add(pattern, func(v interface{}) (interface{}, bool) {
+		if s, ok := v.(string); ok {
+			return strings.ToLower(s), true
+		}
+
+		return v, true
+	})
+}
+
+// Normalize returns a normalized copy of obj. obj isn't changed.
+func (n *Normalizer) Normalize(obj Object) Object {
+	if obj == nil {
+		return nil
+	}
+
+	v, _ := n.normalize(nil, map[string]interface{}(obj))
+	return Set(v.(map[string]interface{}))
+}
+
+// Diff normalizes a and b and diffs the results with diff.JSON
+func (n *Normalizer) Diff(a, b Object) error {
+	return diff.JSON(
+		map[string]interface{}(n.Normalize(a)),
+		map[string]interface{}(n.Normalize(b)),
+	)
+}
+
+// NormalizeMatch normalizes both Objects before Contains or Match compare
+// them
+func NormalizeMatch(n *Normalizer) MatchOption {
+	return func(c *matchConfig) {
+		c.normalizer = n
+	}
+}
+
+func (n *Normalizer) add(pattern string, apply func(v interface{}) (interface{}, bool)) *Normalizer {
+	n.rules = append(n.rules, normalizeRule{pattern: compilePattern(pattern), apply: apply})
+	return n
+}
+
+// normalize copies v, found at p, applying every rule that matches. It
+// returns false when v should be removed from its parent object.
+func (n *Normalizer) normalize(p Path, v interface{}) (interface{}, bool) {
+	if m, ok := asMap(v); ok {
+		out := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			if nv, keep := n.normalize(appendSegment(p, PathSegment{Key: k}), val); keep {
+				out[k] = nv
+			}
+		}
+		v = out
+	} else if a, ok := v.([]interface{}); ok {
+		out := make([]interface{}, len(a))
+		for i, val := range a {
+			out[i], _ = n.normalize(appendSegment(p, PathSegment{Index: i, IsIndex: true}), val)
+		}
+		v = out
+	}
+
+	for _, r := range n.rules {
+		// the root object is never replaced or removed
+		if len(p) == 0 || !matchPattern(r.pattern, p) {
+			continue
+		}
+
+		var keep bool
+		if v, keep = r.apply(v); !keep {
+			return nil, false
+		}
+	}
+
+	return v, true
+}
+
+// appendSegment appends to a copy of p, so siblings don't share backing
+// arrays
+func appendSegment(p Path, s PathSegment) Path {
+	out := make(Path, len(p), len(p)+1)
+	copy(out, p)
+
+	return append(out, s)
+}
+
+// Pattern is a compiled Normalizer pattern, like "users[*].email", for
+// matching the paths Walk visits outside a Normalizer
+type Pattern struct {
+	segments []patternSegment
+}
+
+// CompilePattern compiles a pattern with the wildcards a Normalizer takes
+func CompilePattern(pattern string) Pattern {
+	return Pattern{segments: compilePattern(pattern)}
+}
+
+// Match reports whether p matches the pattern
+func (pt Pattern) Match(p Path) bool {
+	return matchPattern(pt.segments, p)
+}
+
+// compilePattern splits a pattern like "users[*].id" into its segments.
+// The empty pattern matches the root.
+func compilePattern(pattern string) []patternSegment {
+	if pattern == "" {
+		return nil
+	}
+
+	return scanPath(pattern, true)
+}
+
+// matchPattern reports whether the path p matches pattern
+func matchPattern(pattern []patternSegment, p Path) bool {
+	if len(pattern) == 0 {
+		return len(p) == 0
+	}
+
+	if pattern[0].kind == patternAnyDepth {
+		for i := 0; i <= len(p); i++ {
+			if matchPattern(pattern[1:], p[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(p) == 0 {
+		return false
+	}
+
+	s := p[0]
+	switch pattern[0].kind {
+	case patternKey:
+		if s.IsIndex || s.Key != pattern[0].key {
+			return false
+		}
+	case patternAnyKey:
+		if s.IsIndex {
+			return false
+		}
+	case patternIndex:
+		if !s.IsIndex || s.Index != pattern[0].index {
+			return false
+		}
+	case patternAnyIndex:
+		if !s.IsIndex {
+			return false
+		}
+	}
+
+	return matchPattern(pattern[1:], p[1:])
+}
+
+// compareElements orders two array elements by the values at keys
+func compareElements(a, b interface{}, keys []string) int {
+	if len(keys) == 0 {
+		return compareValues(a, b)
+	}
+
+	for _, key := range keys {
+		if c := compareValues(sortKey(a, key), sortKey(b, key)); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+// sortKey returns the value at key in an array element, or nil if there
+// isn't one
+func sortKey(elem interface{}, key string) interface{} {
+	m, ok := asMap(elem)
+	if !ok {
+		return nil
+	}
+
+	v, err := Set(m).Get(key)
+	if err != nil {
+		return nil
+	}
+
+	return v
+}
+
+// compareValues orders JSON scalars: null, then bools, numbers and
+// strings, then anything else
+func compareValues(a, b interface{}) int {
+	ra, rb := valueRank(a), valueRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch ra {
+	case 1:
+		ab, bb := a.(bool), b.(bool)
+		if ab == bb {
+			return 0
+		}
+		if !ab {
+			return -1
+		}
+		return 1
+	case 2:
+		af, _ := number.Float64(a)
+		bf, _ := number.Float64(b)
+		switch {
+		case af < bf:
+			return -1
+		case af > bf:
+			return 1
+		}
+		return 0
+	case 3:
+		return strings.Compare(a.(string), b.(string))
+	}
+
+	return 0
+}
+
+func valueRank(v interface{}) int {
+	if v == nil {
+		return 0
+	}
+
+	if _, ok := v.(bool); ok {
+		return 1
+	}
+
+	if _, ok := number.Float64(v); ok {
+		return 2
+	}
+
+	if _, ok := v.(string); ok {
+		return 3
+	}
+
+	return 4
+}
@@ -0,0 +1,248 @@
+package jsont
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestNormalizer_Normalize(tt *testing.T) {
+	payload := Object{
+		"id":    "3f1c",
+		"total": 10.4567,
+		"note":  nil,
+		"users": []interface{}{
+			map[string]interface{}{"id": "b2", "name": "Carol", "email": "CAROL@EXAMPLE.COM", "manager": nil},
+			map[string]interface{}{"id": "a1", "name": "alice", "email": "Alice@Example.com"},
+			map[string]interface{}{"name": "bob"},
+		},
+		"tags": []interface{}{"b", nil, "a"},
+	}
+
+	testcases := []struct {
+		name       string
+		normalizer *Normalizer
+		expected   Object
+	}{
+		{
+			name:       "replaces values matched by wildcards",
+			normalizer: NewNormalizer().Replace("**.id", "<uuid>"),
+			expected: Object{
+				"id":    "<uuid>",
+				"total": 10.4567,
+				"note":  nil,
+				"users": []interface{}{
+					map[string]interface{}{"id": "<uuid>", "name": "Carol", "email": "CAROL@EXAMPLE.COM", "manager": nil},
+					map[string]interface{}{"id": "<uuid>", "name": "alice", "email": "Alice@Example.com"},
+					map[string]interface{}{"name": "bob"},
+				},
+				"tags": []interface{}{"b", nil, "a"},
+			},
+		},
+		{
+			name: "rounds floats, lowercases strings and strips nulls",
+			normalizer: NewNormalizer().
+				RoundFloats("total", 2).
+				Lowercase("users[*].email").
+				StripNulls("**"),
+			expected: Object{
+				"id":    "3f1c",
+				"total": 10.46,
+				"users": []interface{}{
+					map[string]interface{}{"id": "b2", "name": "Carol", "email": "carol@example.com"},
+					map[string]interface{}{"id": "a1", "name": "alice", "email": "alice@example.com"},
+					map[string]interface{}{"name": "bob"},
+				},
+				"tags": []interface{}{"b", nil, "a"},
+			},
+		},
+		{
+			name:       "sorts arrays by a key, after normalizing their elements",
+			normalizer: NewNormalizer().Lowercase("users[*].name").SortBy("users", "name").SortBy("tags"),
+			expected: Object{
+				"id":    "3f1c",
+				"total": 10.4567,
+				"note":  nil,
+				"users": []interface{}{
+					map[string]interface{}{"id": "a1", "name": "alice", "email": "Alice@Example.com"},
+					map[string]interface{}{"name": "bob"},
+					map[string]interface{}{"id": "b2", "name": "carol", "email": "CAROL@EXAMPLE.COM", "manager": nil},
+				},
+				"tags": []interface{}{nil, "a", "b"},
+			},
+		},
+		{
+			name:       "only replaces values at exact paths",
+			normalizer: NewNormalizer().Replace("users[0].id", "<uuid>").Replace("*.name", "x"),
+			expected: Object{
+				"id":    "3f1c",
+				"total": 10.4567,
+				"note":  nil,
+				"users": []interface{}{
+					map[string]interface{}{"id": "<uuid>", "name": "Carol", "email": "CAROL@EXAMPLE.COM", "manager": nil},
+					map[string]interface{}{"id": "a1", "name": "alice", "email": "Alice@Example.com"},
+					map[string]interface{}{"name": "bob"},
+				},
+				"tags": []interface{}{"b", nil, "a"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			got := tc.normalizer.Normalize(payload)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, got)
+			}
+		})
+	}
+
+	if payload["id"] != "3f1c" || payload["total"] != 10.4567 {
+		tt.Errorf("expected the payload to be left alone, got %v", payload)
+	}
+
+	tt.Run("matches keys quoted in brackets", func(t *testing.T) {
+		labels := Object{"labels": map[string]interface{}{
+			"app.id": "a1",
+			"app":    map[string]interface{}{"id": "a2"},
+			"*":      "any",
+			"env":    "prod",
+		}}
+
+		got := NewNormalizer().Replace(`labels["app.id"]`, "<id>").Replace(`labels["*"]`, "<star>").Normalize(labels)
+
+		expected := Object{"labels": map[string]interface{}{
+			"app.id": "<id>",
+			"app":    map[string]interface{}{"id": "a2"},
+			"*":      "<star>",
+			"env":    "prod",
+		}}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected: %v\ngot: %v", expected, got)
+		}
+	})
+}
+
+func TestNormalizer_SortBy(tt *testing.T) {
+	user := func(last, first string, id float64) map[string]interface{} {
+		return map[string]interface{}{"name": map[string]interface{}{"last": last, "first": first}, "id": id}
+	}
+
+	payload := Object{"users": []interface{}{
+		user("smith", "bob", 3),
+		user("jones", "carol", 10),
+		user("smith", "alice", 2),
+		map[string]interface{}{"id": float64(1)},
+		user("smith", "alice", 1),
+	}}
+
+	testcases := []struct {
+		name     string
+		keys     []string
+		expected []interface{}
+	}{
+		{
+			name: "sorts by one key",
+			keys: []string{"id"},
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				user("smith", "alice", 1),
+				user("smith", "alice", 2),
+				user("smith", "bob", 3),
+				user("jones", "carol", 10),
+			},
+		},
+		{
+			name: "breaks ties with later keys",
+			keys: []string{"name.last", "name.first", "id"},
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				user("jones", "carol", 10),
+				user("smith", "alice", 1),
+				user("smith", "alice", 2),
+				user("smith", "bob", 3),
+			},
+		},
+		{
+			name: "keeps the original order of ties",
+			keys: []string{"name.last"},
+			expected: []interface{}{
+				map[string]interface{}{"id": float64(1)},
+				user("jones", "carol", 10),
+				user("smith", "bob", 3),
+				user("smith", "alice", 2),
+				user("smith", "alice", 1),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			got := NewNormalizer().SortBy("users", tc.keys...).Normalize(payload)
+			if !reflect.DeepEqual(got["users"], tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, got["users"])
+			}
+		})
+	}
+}
+
+func TestNormalizer_BeforeComparing(tt *testing.T) {
+	n := NewNormalizer().Replace("**.id", "<uuid>").SortBy("users", "name")
+
+	a := Object{"id": "1", "users": []interface{}{
+		map[string]interface{}{"id": "2", "name": "bob"},
+		map[string]interface{}{"id": "3", "name": "alice"},
+	}}
+	b := Object{"id": "4", "users": []interface{}{
+		map[string]interface{}{"id": "5", "name": "alice"},
+		map[string]interface{}{"id": "6", "name": "carol"},
+	}}
+
+	testcases := []struct {
+		name  string
+		check func() error
+		err   error
+	}{
+		{
+			name:  "diffs normalized objects",
+			check: func() error { return n.Diff(a, b) },
+			err:   errors.New(`users[1].name: A has "bob"; B has "carol"`),
+		},
+		{
+			name:  "matches normalized objects",
+			check: func() error { return Match(a, b, NormalizeMatch(n)) },
+			err:   errors.New(`users[1].name: wanted "carol"; got "bob"`),
+		},
+		{
+			name:  "contains normalized objects",
+			check: func() error { return Contains(a, Object{"id": "9"}, NormalizeMatch(n)) },
+			err:   nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			if testErr := compare.Errors(tc.err, tc.check()); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+
+	tt.Run("normalizes golden payloads", func(t *testing.T) {
+		dir := t.TempDir()
+		golden := "{\n  \"id\": \"<uuid>\",\n  \"users\": [\n    {\n      \"id\": \"<uuid>\",\n      \"name\": \"alice\"\n    }\n  ]\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, "users.golden.json"), []byte(golden), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		ft := &fakeT{}
+		MatchGolden(ft, "users", []byte(`{"id": "7", "users": [{"name": "alice", "id": "8"}]}`), GoldenDir(dir), NormalizeGolden(n))
+		if len(ft.errs) > 0 {
+			t.Errorf("expected no reported failures, got %v", ft.errs)
+		}
+	})
+}
@@ -79,6 +79,9 @@ func (o Object) HasKeys(propertyPaths []string) (bool, error) {
 }
 
 // Get is used to when you ask the question, "What value is associated with this property path?"
+//
+// Property paths are keys separated by dots, and may index into arrays, like "users[0].name".
+// Keys containing dots or brackets are quoted, like `labels["app.name"]`
 func (o Object) Get(propertyPath string) (interface{}, error) {
 	val, err := parsePathValue(o, propertyPath)
 	if err != nil {
@@ -165,13 +168,11 @@ func (o Object) GetSlice(propertyPath string) ([]interface{}, error) {
 
 // GetObj is used to extract a key whose value is an object
 func (o Object) GetObj(propertyPath string) (Object, error) {
-	fmt.Println("getting value", propertyPath)
 	val, err := parsePathValue(o, propertyPath)
 	if err != nil {
 		return nil, err
 	}
 
-	fmt.Printf("got %v\n", val)
 	m, ok := val.(map[string]interface{})
 	if !ok {
 		return nil, NewTypeCastError(objType, reflect.TypeOf(val))
@@ -180,3 +181,10 @@ func (o Object) GetObj(propertyPath string) (Object, error) {
 	var obj Object = m
 	return obj, nil
 }
+
+// Delete removes the key at a property path from the object it's in. Only
+// object keys can be deleted, so a path ending in an array index returns
+// ErrPathIndexFailed.
+func (o Object) Delete(propertyPath string) error {
+	return deletePathValue(o, propertyPath)
+}
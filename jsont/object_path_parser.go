@@ -1,14 +1,22 @@
 package jsont
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dedwardstech/test/internal/path"
+)
 
 type pathParseFn func(interface{}) (interface{}, error)
 
-func parsePathValue(m map[string]interface{}, propertyPath string) (val interface{}, err error) {
-	filter := parsePath(propertyPath)
+func parsePathValue(m map[string]interface{}, propertyPath string) (interface{}, error) {
+	return valueAt(m, splitPath(propertyPath))
+}
 
+// valueAt finds the value at the path segments under m
+func valueAt(m map[string]interface{}, segments Path) (val interface{}, err error) {
 	val = m
-	for _, fn := range filter {
+	for _, fn := range parsePath(segments) {
 		val, err = fn(val)
 		if err != nil {
 			return nil, err
@@ -18,13 +26,17 @@ func parsePathValue(m map[string]interface{}, propertyPath string) (val interfac
 	return val, nil
 }
 
-// returns a filter chain that parses a property path string
-func parsePath(propertyPath string) []pathParseFn {
-	pathParts := strings.Split(propertyPath, ".")
-	filter := make([]pathParseFn, len(pathParts))
+// returns a filter chain that follows the segments of a property path,
+// like "users[0].name"
+func parsePath(segments Path) []pathParseFn {
+	filter := make([]pathParseFn, len(segments))
 
-	for i, part := range pathParts {
-		filter[i] = get(part)
+	for i, s := range segments {
+		if s.IsIndex {
+			filter[i] = index(s.Index)
+		} else {
+			filter[i] = get(s.Key)
+		}
 	}
 
 	return filter
@@ -32,7 +44,7 @@ func parsePath(propertyPath string) []pathParseFn {
 
 func get(key string) pathParseFn {
 	return func(v interface{}) (interface{}, error) {
-		m, ok := v.(map[string]interface{})
+		m, ok := asMap(v)
 		if !ok {
 			return nil, ErrPathIndexFailed
 		}
@@ -45,3 +57,244 @@ func get(key string) pathParseFn {
 		return m[key], nil
 	}
 }
+
+func index(i int) pathParseFn {
+	return func(v interface{}) (interface{}, error) {
+		a, ok := v.([]interface{})
+		if !ok {
+			return nil, ErrPathIndexFailed
+		}
+
+		if i >= len(a) {
+			return nil, ErrPropertyDoesNotExist
+		}
+
+		return a[i], nil
+	}
+}
+
+// parentAt finds the value holding the one at a property path, along with
+// the last segment of the path
+func parentAt(m map[string]interface{}, propertyPath string) (interface{}, PathSegment, error) {
+	segments := splitPath(propertyPath)
+	last := segments[len(segments)-1]
+
+	parent, err := valueAt(m, segments[:len(segments)-1])
+	return parent, last, err
+}
+
+// setPathValue replaces the value at a property path that already exists
+func setPathValue(m map[string]interface{}, propertyPath string, v interface{}) error {
+	parent, last, err := parentAt(m, propertyPath)
+	if err != nil {
+		return err
+	}
+
+	if last.IsIndex {
+		a, ok := parent.([]interface{})
+		if !ok {
+			return ErrPathIndexFailed
+		}
+
+		if last.Index >= len(a) {
+			return ErrPropertyDoesNotExist
+		}
+
+		a[last.Index] = v
+		return nil
+	}
+
+	pm, ok := asMap(parent)
+	if !ok {
+		return ErrPathIndexFailed
+	}
+
+	if _, ok := pm[last.Key]; !ok {
+		return ErrPropertyDoesNotExist
+	}
+
+	pm[last.Key] = v
+	return nil
+}
+
+// deletePathValue removes the object key at a property path
+func deletePathValue(m map[string]interface{}, propertyPath string) error {
+	parent, last, err := parentAt(m, propertyPath)
+	if err != nil {
+		return err
+	}
+
+	pm, ok := asMap(parent)
+	if !ok || last.IsIndex {
+		return ErrPathIndexFailed
+	}
+
+	if _, ok := pm[last.Key]; !ok {
+		return ErrPropertyDoesNotExist
+	}
+
+	delete(pm, last.Key)
+	return nil
+}
+
+// PathSegment is one step of a Path: an object key, or an array index
+// when IsIndex is set.
+type PathSegment struct {
+	Key     string
+	Index   int
+	IsIndex bool
+}
+
+// Path is where a value is in an Object
+type Path []PathSegment
+
+// String formats the Path in the grammar Get accepts, like "users[0].name"
+func (p Path) String() string {
+	var s string
+	for _, seg := range p {
+		if seg.IsIndex {
+			s = path.Index(s, seg.Index)
+		} else {
+			s = path.Key(s, seg.Key)
+		}
+	}
+
+	return s
+}
+
+// Last returns the final segment of the Path: the key or index the value
+// is found under. It's the zero PathSegment for an empty Path.
+func (p Path) Last() PathSegment {
+	if len(p) == 0 {
+		return PathSegment{}
+	}
+
+	return p[len(p)-1]
+}
+
+// splitPath splits a path like "users[0].name" into its segments. It's the
+// format diff.JSON and Mismatch report paths in, and the one Path.String
+// produces, so keys quoted in brackets, like `labels["app.name"]`, are
+// single segments.
+func splitPath(propertyPath string) Path {
+	scanned := scanPath(propertyPath, false)
+
+	segments := make(Path, len(scanned))
+	for i, s := range scanned {
+		segments[i] = PathSegment{Key: s.key, Index: s.index, IsIndex: s.kind == patternIndex}
+	}
+
+	return segments
+}
+
+type patternKind int
+
+const (
+	patternKey patternKind = iota
+	patternAnyKey
+	patternIndex
+	patternAnyIndex
+	patternAnyDepth
+)
+
+// patternSegment is one step of a path as it's scanned, which may be a
+// wildcard when it's part of a Normalizer pattern
+type patternSegment struct {
+	kind  patternKind
+	key   string
+	index int
+}
+
+// scanPath reads the segments of a property path. With wildcards set, "*"
+// and "**" keys and "[*]" indexes are read as wildcards; keys quoted in
+// brackets, like `["*"]`, never are.
+func scanPath(propertyPath string, wildcards bool) []patternSegment {
+	var segments []patternSegment
+	var key strings.Builder
+
+	flush := func() {
+		k := key.String()
+		key.Reset()
+
+		switch {
+		case wildcards && k == "**":
+			segments = append(segments, patternSegment{kind: patternAnyDepth})
+		case wildcards && k == "*":
+			segments = append(segments, patternSegment{kind: patternAnyKey})
+		default:
+			segments = append(segments, patternSegment{kind: patternKey, key: k})
+		}
+	}
+
+	// pending is set while a key segment is being read; a path that ends in
+	// "." ends in an empty key
+	pending := true
+	for i := 0; i < len(propertyPath); {
+		c := propertyPath[i]
+		if c == '.' {
+			if pending {
+				flush()
+			}
+			pending = true
+			i++
+			continue
+		}
+
+		if c == '[' {
+			if seg, n, ok := bracketSegment(propertyPath[i:], wildcards); ok {
+				if key.Len() > 0 {
+					flush()
+				}
+				segments = append(segments, seg)
+				pending = false
+				i += n
+				continue
+			}
+		}
+
+		key.WriteByte(c)
+		pending = true
+		i++
+	}
+
+	if pending {
+		flush()
+	}
+
+	return segments
+}
+
+// bracketSegment reads an index, like "[0]", a quoted key, like `["a.b"]`,
+// or with wildcards set "[*]", from the start of s, returning how many
+// bytes it took up
+func bracketSegment(s string, wildcards bool) (patternSegment, int, bool) {
+	if wildcards && strings.HasPrefix(s, "[*]") {
+		return patternSegment{kind: patternAnyIndex}, 3, true
+	}
+
+	if strings.HasPrefix(s, `["`) {
+		quoted, err := strconv.QuotedPrefix(s[1:])
+		if err != nil || !strings.HasPrefix(s[1+len(quoted):], "]") {
+			return patternSegment{}, 0, false
+		}
+
+		key, err := strconv.Unquote(quoted)
+		if err != nil {
+			return patternSegment{}, 0, false
+		}
+
+		return patternSegment{kind: patternKey, key: key}, len(quoted) + 2, true
+	}
+
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return patternSegment{}, 0, false
+	}
+
+	i, err := strconv.Atoi(s[1:end])
+	if err != nil || i < 0 {
+		return patternSegment{}, 0, false
+	}
+
+	return patternSegment{kind: patternIndex, index: i}, end + 1, true
+}
@@ -255,6 +255,49 @@ func TestObject_Get(tt *testing.T) {
 			expected: nil,
 			err:      ErrPropertyDoesNotExist,
 		},
+		{
+			name: "gets a value by array index",
+			path: "foo[1].bar[0]",
+			obj: Object{
+				"foo": []interface{}{
+					"skipped",
+					map[string]interface{}{
+						"bar": []interface{}{"baz"},
+					},
+				},
+			},
+			expected: "baz",
+			err:      nil,
+		},
+		{
+			name: "gets a value under a quoted key",
+			path: `foo["app.name"].bar`,
+			obj: Object{
+				"foo": map[string]interface{}{
+					"app.name": map[string]interface{}{"bar": "baz"},
+				},
+			},
+			expected: "baz",
+			err:      nil,
+		},
+		{
+			name: "throws an error if the index is out of range",
+			path: "foo[2]",
+			obj: Object{
+				"foo": []interface{}{1.0, 2.0},
+			},
+			expected: nil,
+			err:      ErrPropertyDoesNotExist,
+		},
+		{
+			name: "throws an error if you index into something that isn't an array",
+			path: "foo[0]",
+			obj: Object{
+				"foo": map[string]interface{}{},
+			},
+			expected: nil,
+			err:      ErrPathIndexFailed,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -783,3 +826,54 @@ func TestObject_GetObj(tt *testing.T) {
 		})
 	}
 }
+
+func TestObject_Delete(tt *testing.T) {
+	testcases := []struct {
+		name, path string
+		expected   Object
+		err        error
+	}{
+		{
+			name:     "deletes a nested key",
+			path:     "foo.bar",
+			expected: Object{"foo": map[string]interface{}{"baz": 1.0}, "list": []interface{}{map[string]interface{}{"id": 1.0}}},
+			err:      nil,
+		},
+		{
+			name:     "deletes a key inside an array element",
+			path:     "list[0].id",
+			expected: Object{"foo": map[string]interface{}{"bar": true, "baz": 1.0}, "list": []interface{}{map[string]interface{}{}}},
+			err:      nil,
+		},
+		{
+			name: "throws an error if the path does not exist",
+			path: "foo.missing",
+			err:  ErrPropertyDoesNotExist,
+		},
+		{
+			name: "throws an error if the path ends in an array index",
+			path: "list[0]",
+			err:  ErrPathIndexFailed,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj := Object{
+				"foo":  map[string]interface{}{"bar": true, "baz": 1.0},
+				"list": []interface{}{map[string]interface{}{"id": 1.0}},
+			}
+
+			err := obj.Delete(tc.path)
+			testErr := compare.Errors(tc.err, err)
+			if testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected value: %v, got: %v", tc.expected, obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package openapi
+
+// convert30 copies an OpenAPI 3.0 document, rewriting the schema keywords
+// that 3.0 uses differently from JSON Schema draft 2020-12:
+//
+//	{"type": "string", "nullable": true} => {"type": ["string", "null"]}
+//	{"minimum": 1, "exclusiveMinimum": true} => {"exclusiveMinimum": 1}
+//
+// Only schemas are rewritten, so a property or example that happens to be
+// called "nullable" is left alone.
+func convert30(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []interface{}:
+		list := make([]interface{}, len(val))
+		for i, elem := range val {
+			list[i] = convert30(elem)
+		}
+
+		return list
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(val))
+		for k, elem := range val {
+			switch k {
+			case "schema":
+				m[k] = convertSchema(elem)
+			case "schemas":
+				m[k] = convertSchemaMap(elem)
+			case "example", "examples":
+				m[k] = elem
+			default:
+				m[k] = convert30(elem)
+			}
+		}
+
+		return m
+	}
+
+	return v
+}
+
+// Keywords whose values are a schema, a list of schemas or a map of names
+// to schemas
+var (
+	subschemaKeywords = map[string]bool{
+		"items": true, "not": true, "additionalProperties": true, "contains": true,
+		"propertyNames": true, "if": true, "then": true, "else": true,
+		"additionalItems": true, "unevaluatedItems": true, "unevaluatedProperties": true,
+	}
+	subschemaListKeywords = map[string]bool{
+		"allOf": true, "anyOf": true, "oneOf": true, "prefixItems": true,
+	}
+	subschemaMapKeywords = map[string]bool{
+		"properties": true, "patternProperties": true, "$defs": true,
+		"definitions": true, "dependentSchemas": true,
+	}
+)
+
+// convertSchema copies a schema, rewriting it and its subschemas. Values of
+// other keywords, like example and enum, are copied as they are.
+func convertSchema(v interface{}) interface{} {
+	val, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	m := make(map[string]interface{}, len(val))
+	for k, elem := range val {
+		switch {
+		case subschemaKeywords[k]:
+			m[k] = convertSchema(elem)
+		case subschemaListKeywords[k]:
+			if list, ok := elem.([]interface{}); ok {
+				converted := make([]interface{}, len(list))
+				for i, s := range list {
+					converted[i] = convertSchema(s)
+				}
+				elem = converted
+			}
+			m[k] = elem
+		case subschemaMapKeywords[k]:
+			m[k] = convertSchemaMap(elem)
+		default:
+			m[k] = elem
+		}
+	}
+
+	if nullable, ok := m["nullable"].(bool); ok {
+		if nullable {
+			if t, ok := m["type"].(string); ok {
+				m["type"] = []interface{}{t, "null"}
+			}
+
+			if enum, ok := m["enum"].([]interface{}); ok {
+				m["enum"] = append(enum[:len(enum):len(enum)], nil)
+			}
+		}
+		delete(m, "nullable")
+	}
+
+	convertExclusive(m, "exclusiveMinimum", "minimum")
+	convertExclusive(m, "exclusiveMaximum", "maximum")
+
+	return m
+}
+
+// convertSchemaMap copies a map of names to schemas, converting each one
+func convertSchemaMap(v interface{}) interface{} {
+	val, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	m := make(map[string]interface{}, len(val))
+	for name, s := range val {
+		m[name] = convertSchema(s)
+	}
+
+	return m
+}
+
+// convertExclusive turns a boolean exclusive bound into a numeric one.
+// Numeric exclusive bounds are already in the 2020-12 form.
+func convertExclusive(m map[string]interface{}, exclusive, bound string) {
+	b, ok := m[exclusive].(bool)
+	if !ok {
+		return
+	}
+
+	delete(m, exclusive)
+	if b {
+		if limit, ok := m[bound]; ok {
+			m[exclusive] = limit
+			delete(m, bound)
+		}
+	}
+}
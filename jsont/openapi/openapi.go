@@ -0,0 +1,226 @@
+// Package openapi validates HTTP response bodies against the schemas an
+// OpenAPI 3.x document declares for them. Specs are read from local files
+// only; every $ref has to point inside the document.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/jsont"
+	"github.com/dedwardstech/test/jsont/schema"
+)
+
+var (
+	// ErrUnsupportedVersion indicates the document isn't an OpenAPI 3.x spec
+	ErrUnsupportedVersion = errors.New("only OpenAPI 3.x documents are supported")
+
+	// ErrOperationNotFound indicates the spec has no operation for the
+	// method and path template given.
+	ErrOperationNotFound = errors.New("operation not found in spec")
+
+	// ErrResponseNotFound indicates the operation declares no response for
+	// the status code given, not even a default one.
+	ErrResponseNotFound = errors.New("response not declared for status code")
+)
+
+// Spec is a loaded OpenAPI document
+type Spec struct {
+	doc jsont.Object
+
+	mu      sync.Mutex
+	schemas map[string]*schema.Schema
+}
+
+// Load parses an OpenAPI document written as JSON
+func Load(spec []byte) (*Spec, error) {
+	doc, err := jsont.Unmarshal(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(doc)
+}
+
+// New wraps an OpenAPI document that has already been decoded. OpenAPI
+// 3.0 schemas are a dialect of JSON Schema, so keywords like nullable are
+// rewritten into their draft 2020-12 equivalents.
+func New(doc jsont.Object) (*Spec, error) {
+	version, err := doc.GetStr("openapi")
+	if err != nil || !strings.HasPrefix(version, "3.") {
+		return nil, ErrUnsupportedVersion
+	}
+
+	if strings.HasPrefix(version, "3.0") {
+		doc = jsont.Set(convert30(map[string]interface{}(doc)).(map[string]interface{}))
+	}
+
+	return &Spec{doc: doc, schemas: make(map[string]*schema.Schema)}, nil
+}
+
+// ValidateResponse validates a response body against the schema declared
+// for the operation at method and pathTemplate, such as "GET" and
+// "/users/{id}". The response for status is looked up by its exact code,
+// then its range (like "2XX"), then "default".
+//
+// A nil error means the body is valid, or nothing was declared to check it
+// against. Otherwise the error is a schema.ValidationError or explains why
+// no schema could be found.
+func (s *Spec) ValidateResponse(method, pathTemplate string, status int, body []byte) error {
+	ptr, err := s.responseSchema(method, pathTemplate, status)
+	if err != nil || ptr == "" {
+		return err
+	}
+
+	sch, err := s.compile(ptr)
+	if err != nil {
+		return err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return errors.Wrap(err, "response body is not valid JSON")
+	}
+
+	return sch.ValidateValue(v)
+}
+
+// responseSchema returns a JSON Pointer to the JSON schema of a response.
+// The pointer is empty when the response has no JSON content.
+func (s *Spec) responseSchema(method, pathTemplate string, status int) (string, error) {
+	ptr := "/paths/" + escape(pathTemplate) + "/" + strings.ToLower(method)
+	if _, err := s.get(ptr); err != nil {
+		return "", errors.Wrapf(ErrOperationNotFound, "%s %s", strings.ToUpper(method), pathTemplate)
+	}
+
+	var response string
+	for _, code := range []string{strconv.Itoa(status), fmt.Sprintf("%dXX", status/100), "default"} {
+		if _, err := s.get(ptr + "/responses/" + code); err == nil {
+			response = ptr + "/responses/" + code
+			break
+		}
+	}
+
+	if response == "" {
+		return "", errors.Wrapf(ErrResponseNotFound, "%s %s %d", strings.ToUpper(method), pathTemplate, status)
+	}
+
+	// Responses are often shared through components/responses
+	response, err := s.followRef(response)
+	if err != nil {
+		return "", err
+	}
+
+	v, err := s.get(response + "/content")
+	if err != nil {
+		return "", nil
+	}
+
+	content, _ := v.(map[string]interface{})
+	mediaType := jsonMediaType(content)
+	if mediaType == "" {
+		return "", nil
+	}
+
+	schemaPtr := response + "/content/" + escape(mediaType) + "/schema"
+	if _, err := s.get(schemaPtr); err != nil {
+		return "", nil
+	}
+
+	return schemaPtr, nil
+}
+
+func (s *Spec) compile(ptr string) (*schema.Schema, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sch, ok := s.schemas[ptr]; ok {
+		return sch, nil
+	}
+
+	sch, err := schema.CompileAt(s.doc, ptr)
+	if err != nil {
+		return nil, err
+	}
+
+	s.schemas[ptr] = sch
+	return sch, nil
+}
+
+// followRef resolves the $ref of the object at ptr, if it has one
+func (s *Spec) followRef(ptr string) (string, error) {
+	for i := 0; i < 32; i++ {
+		v, err := s.get(ptr)
+		if err != nil {
+			return "", err
+		}
+
+		m, _ := v.(map[string]interface{})
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return ptr, nil
+		}
+
+		if !strings.HasPrefix(ref, "#") {
+			return "", errors.Errorf("%q is not a local reference; only references within the spec are supported", ref)
+		}
+		ptr = ref[1:]
+	}
+
+	return "", errors.Errorf("too many nested references at %q", ptr)
+}
+
+// get finds the value at a JSON Pointer in the document
+func (s *Spec) get(ptr string) (interface{}, error) {
+	var v interface{} = map[string]interface{}(s.doc)
+
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, jsont.ErrPathIndexFailed
+		}
+
+		if v, ok = m[token]; !ok {
+			return nil, jsont.ErrPropertyDoesNotExist
+		}
+	}
+
+	return v, nil
+}
+
+// jsonMediaType picks the JSON media type out of a response's content,
+// preferring application/json over other +json types.
+func jsonMediaType(content map[string]interface{}) string {
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		if strings.HasPrefix(t, "application/json") {
+			return t
+		}
+	}
+
+	for _, t := range types {
+		base := strings.TrimSpace(strings.SplitN(t, ";", 2)[0])
+		if strings.HasSuffix(base, "+json") {
+			return t
+		}
+	}
+
+	return ""
+}
+
+func escape(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -0,0 +1,197 @@
+package openapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+const petstore = `{
+  "openapi": "3.0.3",
+  "info": {"title": "pets", "version": "1"},
+  "paths": {
+    "/pets/{id}": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "a pet",
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+          },
+          "4XX": {"$ref": "#/components/responses/Error"},
+          "default": {"description": "no body"}
+        }
+      }
+    },
+    "/pets": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "pets",
+            "content": {
+              "application/problem+json": {"schema": {"type": "object"}},
+              "application/json; charset=utf-8": {
+                "schema": {"type": "array", "items": {"$ref": "#/components/schemas/Pet"}}
+              }
+            }
+          }
+        }
+      }
+    }
+  },
+  "components": {
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "required": ["id", "name"],
+        "properties": {
+          "id": {"type": "integer", "minimum": 0, "exclusiveMinimum": true},
+          "name": {"type": "string"},
+          "tag": {"type": "string", "nullable": true}
+        }
+      }
+    },
+    "responses": {
+      "Error": {
+        "description": "an error",
+        "content": {
+          "application/json": {
+            "schema": {"type": "object", "required": ["message"]}
+          }
+        }
+      }
+    }
+  }
+}`
+
+func TestSpec_ValidateResponse(tt *testing.T) {
+	spec, err := Load([]byte(petstore))
+	if err != nil {
+		tt.Fatalf("failed to load spec: %v", err)
+	}
+
+	testcases := []struct {
+		name, method, path string
+		status             int
+		body               string
+		err                error
+	}{
+		{
+			name:   "accepts a body that matches the schema",
+			method: "GET", path: "/pets/{id}", status: 200,
+			body: `{"id": 1, "name": "rex", "tag": null}`,
+			err:  nil,
+		},
+		{
+			name:   "reports violations with instance pointers",
+			method: "get", path: "/pets/{id}", status: 200,
+			body: `{"id": 0, "tag": 1}`,
+			err:  errors.New("(root): required: missing property \"name\"\n/id: exclusiveMinimum: 0 is not greater than 0\n/tag: type: wanted string or null; got integer"),
+		},
+		{
+			name:   "falls back to status code ranges and shared responses",
+			method: "GET", path: "/pets/{id}", status: 404,
+			body: `{}`,
+			err:  errors.New(`(root): required: missing property "message"`),
+		},
+		{
+			name:   "skips responses without JSON content",
+			method: "GET", path: "/pets/{id}", status: 500,
+			body: ``,
+			err:  nil,
+		},
+		{
+			name:   "prefers application/json content",
+			method: "GET", path: "/pets", status: 200,
+			body: `[{"id": 1, "name": "rex"}, {"id": 2}]`,
+			err:  errors.New(`/1: required: missing property "name"`),
+		},
+		{
+			name:   "reports operations missing from the spec",
+			method: "POST", path: "/pets", status: 201,
+			body: `{}`,
+			err:  errors.New("POST /pets: operation not found in spec"),
+		},
+		{
+			name:   "reports undeclared responses",
+			method: "GET", path: "/pets", status: 404,
+			body: `{}`,
+			err:  errors.New("GET /pets 404: response not declared for status code"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := spec.ValidateResponse(tc.method, tc.path, tc.status, []byte(tc.body))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	_, err := Load([]byte(`{"swagger": "2.0"}`))
+	if testErr := compare.Errors(ErrUnsupportedVersion, err); testErr != nil {
+		t.Error(testErr)
+	}
+}
+
+func TestLoad_ConvertsOnlySchemas(tt *testing.T) {
+	spec, err := Load([]byte(`{
+  "openapi": "3.0.3",
+  "paths": {
+    "/columns": {
+      "get": {
+        "responses": {
+          "200": {
+            "description": "a column",
+            "content": {
+              "application/json": {
+                "schema": {
+                  "type": "object",
+                  "required": ["nullable", "exclusiveMinimum"],
+                  "properties": {
+                    "nullable": {"type": "boolean"},
+                    "exclusiveMinimum": {"type": "integer", "minimum": 1}
+                  },
+                  "example": {"nullable": true, "exclusiveMinimum": 2}
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`))
+	if err != nil {
+		tt.Fatalf("failed to load spec: %v", err)
+	}
+
+	testcases := []struct {
+		name string
+		body string
+		err  error
+	}{
+		{
+			name: "keeps properties named after 3.0 keywords",
+			body: `{"nullable": false, "exclusiveMinimum": 1}`,
+			err:  nil,
+		},
+		{
+			name: "validates properties named after 3.0 keywords",
+			body: `{"nullable": "no", "exclusiveMinimum": 0}`,
+			err:  errors.New("/exclusiveMinimum: minimum: 0 is less than 1\n/nullable: type: wanted boolean; got string"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := spec.ValidateResponse("GET", "/columns", 200, []byte(tc.body))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
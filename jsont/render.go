@@ -0,0 +1,268 @@
+package jsont
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dedwardstech/test/internal/path"
+)
+
+const (
+	defaultMaxArrayLen  = 10
+	defaultMaxStringLen = 80
+)
+
+// ANSI escape codes used when rendering in color
+const (
+	ansiReset     = "\x1b[0m"
+	ansiHighlight = "\x1b[1;31m"
+	ansiKey       = "\x1b[36m"
+	ansiString    = "\x1b[32m"
+	ansiNumber    = "\x1b[33m"
+	ansiLiteral   = "\x1b[35m"
+	ansiCollapsed = "\x1b[2m"
+)
+
+// RenderOption configures how Render lays out an Object
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	highlights   map[string]bool
+	color        bool
+	maxArrayLen  int
+	maxStringLen int
+}
+
+// Highlight marks the values at property paths, like "users[0].name". Their
+// lines are prefixed with "> ", and drawn in red when color is on.
+func Highlight(propertyPaths ...string) RenderOption {
+	return func(c *renderConfig) {
+		for _, p := range propertyPaths {
+			c.highlights[p] = true
+		}
+	}
+}
+
+// Color turns ANSI color on or off. By default it's on when stdout is a
+// terminal and NO_COLOR isn't set.
+func Color(on bool) RenderOption {
+	return func(c *renderConfig) {
+		c.color = on
+	}
+}
+
+// MaxArrayLen sets how many elements of an array are shown before the rest
+// are collapsed. Highlighted elements are always shown. Zero shows them
+// all.
+func MaxArrayLen(n int) RenderOption {
+	return func(c *renderConfig) {
+		c.maxArrayLen = n
+	}
+}
+
+// MaxStringLen sets how many characters of a string are shown before the
+// rest are cut off. Zero shows them all.
+func MaxStringLen(n int) RenderOption {
+	return func(c *renderConfig) {
+		c.maxStringLen = n
+	}
+}
+
+// Render lays the Object out as indented JSON, for people rather than
+// parsers: keys are sorted, long arrays and strings are collapsed and
+// highlighted paths are marked.
+func (o Object) Render(opts ...RenderOption) string {
+	cfg := &renderConfig{
+		highlights:   make(map[string]bool),
+		color:        isTerminal(os.Stdout) && os.Getenv("NO_COLOR") == "",
+		maxArrayLen:  defaultMaxArrayLen,
+		maxStringLen: defaultMaxStringLen,
+	}
+
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	r := &renderer{cfg: cfg}
+	r.value("", "", map[string]interface{}(o), 0, false)
+
+	return strings.Join(r.lines, "\n")
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+type renderer struct {
+	cfg   *renderConfig
+	lines []string
+}
+
+// value renders v, found at path p, starting the first line with prefix.
+// highlighted is set when a parent of v is highlighted.
+func (r *renderer) value(p, prefix string, v interface{}, depth int, highlighted bool) {
+	highlighted = highlighted || r.cfg.highlights[p]
+
+	if m, ok := asMap(v); ok {
+		if len(m) == 0 {
+			r.line(depth, prefix+"{}", highlighted)
+			return
+		}
+
+		r.line(depth, prefix+"{", highlighted)
+		keys := sortedKeys(m)
+		for i, k := range keys {
+			kp := path.Key(p, k)
+			r.value(kp, r.color(ansiKey, quote(k))+": ", m[k], depth+1, highlighted)
+			r.comma(i < len(keys)-1)
+		}
+		r.line(depth, "}", highlighted)
+
+		return
+	}
+
+	a, ok := v.([]interface{})
+	if !ok {
+		r.line(depth, prefix+r.scalar(v), highlighted)
+		return
+	}
+
+	if len(a) == 0 {
+		r.line(depth, prefix+"[]", highlighted)
+		return
+	}
+
+	r.line(depth, prefix+"[", highlighted)
+	collapsed := 0
+	for i, elem := range a {
+		ip := path.Index(p, i)
+		if r.cfg.maxArrayLen > 0 && i >= r.cfg.maxArrayLen && !r.highlightedWithin(ip) {
+			collapsed++
+			continue
+		}
+
+		r.collapsed(depth+1, collapsed, highlighted)
+		collapsed = 0
+
+		r.value(ip, "", elem, depth+1, highlighted)
+		r.comma(i < len(a)-1)
+	}
+	r.collapsed(depth+1, collapsed, highlighted)
+	r.line(depth, "]", highlighted)
+}
+
+// highlightedWithin reports whether p, or anything under it, is highlighted
+func (r *renderer) highlightedWithin(p string) bool {
+	for h := range r.cfg.highlights {
+		if h == p || strings.HasPrefix(h, p+".") || strings.HasPrefix(h, p+"[") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *renderer) collapsed(depth, n int, highlighted bool) {
+	if n == 0 {
+		return
+	}
+
+	noun := "elements"
+	if n == 1 {
+		noun = "element"
+	}
+
+	r.line(depth, r.color(ansiCollapsed, fmt.Sprintf("... %d more %s", n, noun)), highlighted)
+}
+
+func (r *renderer) scalar(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return r.color(ansiLiteral, "null")
+	case bool:
+		return r.color(ansiLiteral, fmt.Sprint(t))
+	case string:
+		if n := utf8.RuneCountInString(t); r.cfg.maxStringLen > 0 && n > r.cfg.maxStringLen {
+			q := quote(string([]rune(t)[:r.cfg.maxStringLen]))
+			return r.color(ansiString, q[:len(q)-1]+`..."`) + fmt.Sprintf(" (%d chars)", n)
+		}
+
+		return r.color(ansiString, quote(t))
+	case Matcher:
+		return fmt.Sprint(t)
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+
+	return r.color(ansiNumber, string(b))
+}
+
+// comma ends the last line with a comma when more values follow
+func (r *renderer) comma(more bool) {
+	if more {
+		r.lines[len(r.lines)-1] += ","
+	}
+}
+
+func (r *renderer) line(depth int, text string, highlighted bool) {
+	gutter := ""
+	if len(r.cfg.highlights) > 0 {
+		gutter = "  "
+		if highlighted {
+			gutter = "> "
+		}
+	}
+
+	if highlighted && r.cfg.color {
+		text = ansiHighlight + stripANSI(text) + ansiReset
+	}
+
+	r.lines = append(r.lines, gutter+strings.Repeat("  ", depth)+text)
+}
+
+func (r *renderer) color(code, text string) string {
+	if !r.cfg.color {
+		return text
+	}
+
+	return code + text + ansiReset
+}
+
+// stripANSI removes color codes, so highlighted lines are one color
+func stripANSI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\x1b' {
+			for i < len(s) && s[i] != 'm' {
+				i++
+			}
+			continue
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
+
+// quote writes s as a JSON string, leaving <, > and & alone
+func quote(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,105 @@
+package jsont
+
+import "testing"
+
+func TestObject_Render(tt *testing.T) {
+	obj := Object{
+		"user": map[string]interface{}{
+			"name":  "bob",
+			"bio":   "a very long biography",
+			"roles": []interface{}{"a", "b", "c", "d", "e"},
+			"tags":  []interface{}{},
+		},
+		"active": true,
+		"score":  1.5,
+		"note":   nil,
+	}
+
+	testcases := []struct {
+		name     string
+		opts     []RenderOption
+		expected string
+	}{
+		{
+			name: "renders indented JSON with sorted keys",
+			opts: []RenderOption{Color(false), MaxArrayLen(0), MaxStringLen(0)},
+			expected: `{
+  "active": true,
+  "note": null,
+  "score": 1.5,
+  "user": {
+    "bio": "a very long biography",
+    "name": "bob",
+    "roles": [
+      "a",
+      "b",
+      "c",
+      "d",
+      "e"
+    ],
+    "tags": []
+  }
+}`,
+		},
+		{
+			name: "collapses long arrays and strings",
+			opts: []RenderOption{Color(false), MaxArrayLen(2), MaxStringLen(6)},
+			expected: `{
+  "active": true,
+  "note": null,
+  "score": 1.5,
+  "user": {
+    "bio": "a very..." (21 chars),
+    "name": "bob",
+    "roles": [
+      "a",
+      "b",
+      ... 3 more elements
+    ],
+    "tags": []
+  }
+}`,
+		},
+		{
+			name: "marks highlighted paths and keeps them when collapsing",
+			opts: []RenderOption{Color(false), MaxArrayLen(1), Highlight("user.roles[3]", "score")},
+			expected: `  {
+    "active": true,
+    "note": null,
+>   "score": 1.5,
+    "user": {
+      "bio": "a very long biography",
+      "name": "bob",
+      "roles": [
+        "a",
+        ... 2 more elements
+>       "d",
+        ... 1 more element
+      ],
+      "tags": []
+    }
+  }`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			got := obj.Render(tc.opts...)
+			if got != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, got)
+			}
+		})
+	}
+
+	tt.Run("colors values and highlighted lines", func(t *testing.T) {
+		expected := "  {\n" +
+			"    \x1b[36m\"id\"\x1b[0m: \x1b[33m1\x1b[0m,\n" +
+			">   \x1b[1;31m\"name\": \"bob\"\x1b[0m\n" +
+			"  }"
+
+		got := Object{"id": float64(1), "name": "bob"}.Render(Color(true), Highlight("name"))
+		if got != expected {
+			t.Errorf("expected:\n%q\ngot:\n%q", expected, got)
+		}
+	})
+}
@@ -0,0 +1,316 @@
+package jsont
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dedwardstech/test/diff"
+)
+
+// RoundTripKind says what happened to a value on its way through a Go type
+type RoundTripKind string
+
+const (
+	// RoundTripDropped means the type has no field for the value
+	RoundTripDropped RoundTripKind = "dropped"
+
+	// RoundTripRenamed means the value came back under a key that only
+	// differs in case. encoding/json matches keys case-insensitively.
+	RoundTripRenamed RoundTripKind = "renamed"
+
+	// RoundTripOmitted means an omitempty field left out a zero value
+	RoundTripOmitted RoundTripKind = "omitted"
+
+	// RoundTripAdded means the type wrote a value that wasn't in the payload
+	RoundTripAdded RoundTripKind = "added"
+
+	// RoundTripChanged means the value came back different, usually
+	// because the field's type coerced it.
+	RoundTripChanged RoundTripKind = "changed"
+)
+
+// RoundTripChange is one difference between a payload and what a Go type
+// made of it.
+type RoundTripChange struct {
+	Path   string
+	Kind   RoundTripKind
+	Detail string
+}
+
+// RoundTripError lists every RoundTripChange found by RoundTrip
+type RoundTripError struct {
+	Type    string
+	Changes []RoundTripChange
+}
+
+func (e RoundTripError) Error() string {
+	lines := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		lines[i] = fmt.Sprintf("%s: %s, %s", c.Path, c.Kind, c.Detail)
+	}
+
+	return fmt.Sprintf("payload does not round trip through %s:\n%s", e.Type, strings.Join(lines, "\n"))
+}
+
+// RoundTrip decodes payload into a T, encodes the T again and diffs the
+// result against the original payload. It catches struct tags that have
+// drifted from what a service sends: fields T doesn't know about, keys
+// that only matched case-insensitively, values dropped by omitempty and
+// values changed by a field's type.
+//
+// The error is a RoundTripError, or the error from decoding the payload.
+func RoundTrip[T any](payload []byte) error {
+	var typed T
+	typ := reflect.TypeOf(&typed).Elem()
+
+	if err := json.Unmarshal(payload, &typed); err != nil {
+		return errors.Wrapf(err, "failed to decode payload into %s", typ)
+	}
+
+	encoded, err := json.Marshal(typed)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s", typ)
+	}
+
+	var original, roundTripped interface{}
+	if err := json.Unmarshal(payload, &original); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(encoded, &roundTripped); err != nil {
+		return err
+	}
+
+	d, ok := diff.JSON(original, roundTripped).(diff.JSONDiff)
+	if !ok {
+		return nil
+	}
+
+	return RoundTripError{Type: typ.String(), Changes: classifyRoundTrip(typ, d)}
+}
+
+func classifyRoundTrip(typ reflect.Type, d diff.JSONDiff) []RoundTripChange {
+	var changes []RoundTripChange
+	renamed := make(map[int]bool)
+
+	for i, c := range d {
+		if renamed[i] {
+			continue
+		}
+
+		switch {
+		case c.InA && c.InB:
+			changes = append(changes, RoundTripChange{
+				Path:   c.Path,
+				Kind:   RoundTripChanged,
+				Detail: fmt.Sprintf("sent %s, got back %s", formatValue(c.A), formatValue(c.B)),
+			})
+		case c.InA:
+			if j := findRename(d, i); j >= 0 {
+				renamed[j] = true
+				changes = append(changes, RoundTripChange{
+					Path:   c.Path,
+					Kind:   RoundTripRenamed,
+					Detail: fmt.Sprintf("came back as %s", d[j].Path),
+				})
+				continue
+			}
+
+			segments := splitPath(c.Path)
+			parent, known, omitempty := jsonField(typ, segments)
+			switch {
+			case known && omitempty:
+				changes = append(changes, RoundTripChange{
+					Path:   c.Path,
+					Kind:   RoundTripOmitted,
+					Detail: fmt.Sprintf("omitempty dropped %s", formatValue(c.A)),
+				})
+			default:
+				changes = append(changes, RoundTripChange{
+					Path:   c.Path,
+					Kind:   RoundTripDropped,
+					Detail: droppedDetail(typ, parent, segments, c.A),
+				})
+			}
+		default:
+			changes = append(changes, RoundTripChange{
+				Path:   c.Path,
+				Kind:   RoundTripAdded,
+				Detail: fmt.Sprintf("%s wrote %s", typ, formatValue(c.B)),
+			})
+		}
+	}
+
+	return changes
+}
+
+// droppedDetail says which key the Go type at the parent of a path had no
+// field for
+func droppedDetail(typ, parent reflect.Type, segments Path, v interface{}) string {
+	if parent == nil {
+		return fmt.Sprintf("%s has no field for %s", typ, formatValue(v))
+	}
+
+	n := len(segments)
+	owner := parent.String()
+	if n > 1 {
+		owner = fmt.Sprintf("%s (%s)", segments[:n-1], parent)
+	}
+
+	if segments[n-1].IsIndex {
+		return fmt.Sprintf("%s has no element %d for %s", owner, segments[n-1].Index, formatValue(v))
+	}
+
+	return fmt.Sprintf("%s has no field %q for %s", owner, segments[n-1].Key, formatValue(v))
+}
+
+// findRename looks for a value that came back under the same key with
+// different case, next to the value at d[i] that went missing.
+func findRename(d diff.JSONDiff, i int) int {
+	parent, key := splitLast(d[i].Path)
+	if key.IsIndex {
+		return -1
+	}
+
+	for j, c := range d {
+		if c.InA || !c.InB {
+			continue
+		}
+
+		p, k := splitLast(c.Path)
+		if p == parent && !k.IsIndex && k.Key != key.Key && strings.EqualFold(k.Key, key.Key) && diff.JSON(d[i].A, c.B) == nil {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// splitLast splits a path into its parent's path and its last segment
+func splitLast(p string) (string, PathSegment) {
+	segments := splitPath(p)
+	n := len(segments)
+
+	return segments[:n-1].String(), segments[n-1]
+}
+
+// jsonField finds the struct field encoding/json would decode the value at
+// a path into. It reports whether there is one and whether it's omitempty,
+// along with the type of the value's parent, or nil if the path leaves T
+// before it gets there.
+func jsonField(t reflect.Type, segments Path) (parent reflect.Type, known, omitempty bool) {
+	for i, seg := range segments {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if i == len(segments)-1 {
+			parent = t
+		}
+
+		switch t.Kind() {
+		case reflect.Interface:
+			// anything goes in an interface{}, so nothing is lost
+			return parent, true, false
+		case reflect.Slice, reflect.Array:
+			if !seg.IsIndex {
+				return parent, false, false
+			}
+			t = t.Elem()
+		case reflect.Map:
+			if seg.IsIndex {
+				return parent, false, false
+			}
+			t = t.Elem()
+		case reflect.Struct:
+			if seg.IsIndex {
+				return parent, false, false
+			}
+
+			f, ok := structField(t, seg.Key)
+			if !ok {
+				return parent, false, false
+			}
+
+			if i == len(segments)-1 {
+				return parent, true, f.omitempty
+			}
+			t = f.typ
+		default:
+			return parent, false, false
+		}
+	}
+
+	return parent, true, false
+}
+
+type taggedField struct {
+	typ       reflect.Type
+	omitempty bool
+}
+
+// structField finds the field for a JSON key the way encoding/json does:
+// an exact name match first, then a case-insensitive one. Fields of
+// embedded structs are promoted.
+func structField(t reflect.Type, key string) (taggedField, bool) {
+	var fold *taggedField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, opts = tag[:comma], tag[comma+1:]
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			if embedded, ok := structField(ft, key); ok {
+				return embedded, true
+			}
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		field := taggedField{typ: f.Type, omitempty: strings.Contains(","+opts+",", ",omitempty,")}
+		if name == key {
+			return field, true
+		}
+
+		if fold == nil && strings.EqualFold(name, key) {
+			fold = &field
+		}
+	}
+
+	if fold != nil {
+		return *fold, true
+	}
+
+	return taggedField{}, false
+}
+
+func formatValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+
+	return string(b)
+}
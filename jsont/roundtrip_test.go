@@ -0,0 +1,91 @@
+package jsont
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+type roundTripAddress struct {
+	Zip string `json:"zip"`
+}
+
+type roundTripBase struct {
+	ID string `json:"id"`
+}
+
+type roundTripUser struct {
+	roundTripBase
+	UserName string            `json:"userName"`
+	Age      int               `json:"age,omitempty"`
+	Score    int               `json:"score"`
+	Address  *roundTripAddress `json:"address,omitempty"`
+	Tags     []string          `json:"tags"`
+	Extra    interface{}       `json:"extra,omitempty"`
+	Created  string            `json:"created"`
+	Updated  *time.Time        `json:"updated,omitempty"`
+}
+
+func TestRoundTrip(tt *testing.T) {
+	testcases := []struct {
+		name    string
+		payload string
+		err     error
+	}{
+		{
+			name:    "passes when the payload survives the round trip",
+			payload: `{"id": "u1", "userName": "bob", "age": 3, "score": 0, "address": {"zip": "1"}, "tags": ["a"], "extra": {"any": [1]}, "created": "x"}`,
+			err:     nil,
+		},
+		{
+			name:    "reports fields the type doesn't know about",
+			payload: `{"id": "u1", "userName": "bob", "score": 1, "tags": [], "created": "x", "nickname": "b", "address": {"zip": "1", "city": "x"}}`,
+			err: errors.New(`payload does not round trip through jsont.roundTripUser:
+address.city: dropped, address (jsont.roundTripAddress) has no field "city" for "x"
+nickname: dropped, jsont.roundTripUser has no field "nickname" for "b"`),
+		},
+		{
+			name:    "reports keys containing dots and brackets as single keys",
+			payload: `{"id": "u1", "userName": "bob", "score": 1, "tags": [], "created": "x", "address": {"zip": "1", "geo.lat": 1, "a[0]": 2}}`,
+			err: errors.New(`payload does not round trip through jsont.roundTripUser:
+address["a[0]"]: dropped, address (jsont.roundTripAddress) has no field "a[0]" for 2
+address["geo.lat"]: dropped, address (jsont.roundTripAddress) has no field "geo.lat" for 1`),
+		},
+		{
+			name:    "reports keys that only matched case-insensitively",
+			payload: `{"id": "u1", "UserName": "bob", "score": 1, "tags": [], "created": "x"}`,
+			err: errors.New(`payload does not round trip through jsont.roundTripUser:
+UserName: renamed, came back as userName`),
+		},
+		{
+			name:    "reports zero values dropped by omitempty and values the type adds",
+			payload: `{"id": "u1", "userName": "bob", "age": 0, "tags": null}`,
+			err: errors.New(`payload does not round trip through jsont.roundTripUser:
+age: omitted, omitempty dropped 0
+created: added, jsont.roundTripUser wrote ""
+score: added, jsont.roundTripUser wrote 0`),
+		},
+		{
+			name:    "reports values changed by the field's type",
+			payload: `{"id": "u1", "userName": "bob", "score": 1, "tags": [], "created": "x", "updated": "2021-01-30T12:00:00.000+00:00"}`,
+			err: errors.New(`payload does not round trip through jsont.roundTripUser:
+updated: changed, sent "2021-01-30T12:00:00.000+00:00", got back "2021-01-30T12:00:00Z"`),
+		},
+		{
+			name:    "returns errors decoding the payload",
+			payload: `{"id": "u1", "userName":`,
+			err:     errors.New("failed to decode payload into jsont.roundTripUser: unexpected end of JSON input"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			err := RoundTrip[roundTripUser]([]byte(tc.payload))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation is one way an instance failed to validate. InstancePath is a
+// JSON Pointer to the offending value, Keyword is the schema keyword that
+// failed and SchemaPath is a JSON Pointer to that keyword in the schema.
+type Violation struct {
+	InstancePath string
+	Keyword      string
+	SchemaPath   string
+	Message      string
+}
+
+func (v Violation) String() string {
+	p := v.InstancePath
+	if p == "" {
+		p = "(root)"
+	}
+
+	return fmt.Sprintf("%s: %s: %s", p, v.Keyword, v.Message)
+}
+
+// ValidationError holds every Violation found when validating an instance
+type ValidationError []Violation
+
+func (e ValidationError) Error() string {
+	lines := make([]string, len(e))
+	for i, v := range e {
+		lines[i] = v.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// CompileError indicates the schema itself is invalid, or uses something
+// this package can't resolve, like a remote $ref.
+type CompileError struct {
+	SchemaPath string
+	Message    string
+}
+
+func (e CompileError) Error() string {
+	return fmt.Sprintf("invalid schema at %q: %s", e.SchemaPath, e.Message)
+}
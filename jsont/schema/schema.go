@@ -0,0 +1,610 @@
+// Package schema validates jsont Objects against JSON Schema (draft 2020-12).
+//
+// Schemas are compiled from a jsont.Object, so a schema file is loaded the
+// same way as any other payload:
+//
+//	doc, _ := jsont.Unmarshal(schemaBytes)
+//	s, err := schema.Compile(doc)
+//	...
+//	err = s.Validate(body)
+//
+// References are only resolved within the schema document itself: "#",
+// JSON Pointers like "#/$defs/user" and "$anchor" names. Nothing is ever
+// fetched over the network. Formats are asserted, not just annotated.
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dedwardstech/test/internal/number"
+	"github.com/dedwardstech/test/jsont"
+)
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	root *node
+}
+
+// Compile compiles a JSON Schema document. Every local $ref in it has to
+// resolve, or a CompileError is returned.
+func Compile(doc jsont.Object) (*Schema, error) {
+	return compileAt(map[string]interface{}(doc), "")
+}
+
+// CompileAt compiles the subschema at a JSON Pointer within doc, such as
+// "/components/schemas/User". References are resolved against the whole
+// document, which is how schemas embedded in bigger documents, like OpenAPI
+// specs, get compiled.
+func CompileAt(doc jsont.Object, pointer string) (*Schema, error) {
+	return compileAt(map[string]interface{}(doc), pointer)
+}
+
+func compileAt(doc interface{}, pointer string) (*Schema, error) {
+	c := &compiler{
+		doc:     doc,
+		nodes:   make(map[string]*node),
+		anchors: make(map[string]string),
+	}
+
+	if m, ok := asMap(doc); ok {
+		c.id, _ = m["$id"].(string)
+	}
+	c.findAnchors(doc, "")
+
+	root, err := c.compile(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schema{root: root}, nil
+}
+
+// Validate validates an Object against the schema. The error is nil or a
+// ValidationError listing every violation.
+func (s *Schema) Validate(obj jsont.Object) error {
+	return s.ValidateValue(map[string]interface{}(obj))
+}
+
+// ValidateValue validates any decoded JSON value against the schema, for
+// payloads that aren't objects.
+func (s *Schema) ValidateValue(v interface{}) error {
+	violations, _ := s.root.validate(v, "", "false")
+	if len(violations) > 0 {
+		return ValidationError(violations)
+	}
+
+	return nil
+}
+
+// node is a compiled schema, or subschema. Keywords that weren't in the
+// schema are left nil.
+type node struct {
+	ptr     string
+	boolean *bool
+	ref     *node
+
+	types  []string
+	enum   []interface{}
+	consts []interface{}
+
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+	multipleOf                         *float64
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+	format               string
+
+	prefixItems      []*node
+	items            *node
+	contains         *node
+	minContains      *int
+	maxContains      *int
+	minItems         *int
+	maxItems         *int
+	uniqueItems      bool
+	unevaluatedItems *node
+
+	properties            map[string]*node
+	patternProperties     []patternProperty
+	additionalProperties  *node
+	unevaluatedProperties *node
+	propertyNames         *node
+	required              []string
+	minProperties         *int
+	maxProperties         *int
+	dependentRequired     map[string][]string
+	dependentSchemas      map[string]*node
+
+	allOf, anyOf, oneOf []*node
+	not                 *node
+	ifSchema            *node
+	thenSchema          *node
+	elseSchema          *node
+}
+
+type patternProperty struct {
+	re     *regexp.Regexp
+	schema *node
+}
+
+type compiler struct {
+	doc     interface{}
+	id      string
+	nodes   map[string]*node
+	anchors map[string]string
+}
+
+// compile compiles the subschema at a JSON Pointer into the document.
+// Nodes are cached by pointer before their keywords are compiled, so a
+// schema that refers to itself resolves to the node being built.
+func (c *compiler) compile(ptr string) (*node, error) {
+	if n, ok := c.nodes[ptr]; ok {
+		return n, nil
+	}
+
+	raw, err := resolvePointer(c.doc, ptr)
+	if err != nil {
+		return nil, CompileError{SchemaPath: ptr, Message: err.Error()}
+	}
+
+	n := &node{ptr: ptr}
+	c.nodes[ptr] = n
+
+	if b, ok := raw.(bool); ok {
+		n.boolean = &b
+		return n, nil
+	}
+
+	m, ok := asMap(raw)
+	if !ok {
+		return nil, CompileError{SchemaPath: ptr, Message: "a schema must be an object or a boolean"}
+	}
+
+	if err := c.compileKeywords(n, m); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+func (c *compiler) compileKeywords(n *node, m map[string]interface{}) error {
+	kw := keywords{c: c, n: n, m: m}
+
+	if ref, ok := m["$ref"]; ok {
+		s, ok := ref.(string)
+		if !ok {
+			return kw.invalid("$ref", "must be a string")
+		}
+
+		target, err := c.resolveRef(s)
+		if err != nil {
+			return kw.invalid("$ref", err.Error())
+		}
+
+		if n.ref, err = c.compile(target); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := m["$dynamicRef"]; ok {
+		return kw.invalid("$dynamicRef", "is not supported")
+	}
+
+	if t, ok := m["type"]; ok {
+		switch v := t.(type) {
+		case string:
+			n.types = []string{v}
+		case []interface{}:
+			for _, elem := range v {
+				s, ok := elem.(string)
+				if !ok {
+					return kw.invalid("type", "must be a string or an array of strings")
+				}
+				n.types = append(n.types, s)
+			}
+		default:
+			return kw.invalid("type", "must be a string or an array of strings")
+		}
+	}
+
+	if e, ok := m["enum"]; ok {
+		values, ok := e.([]interface{})
+		if !ok {
+			return kw.invalid("enum", "must be an array")
+		}
+		n.enum = values
+	}
+
+	if v, ok := m["const"]; ok {
+		n.consts = []interface{}{v}
+	}
+
+	if s, ok := m["format"].(string); ok {
+		n.format = s
+	}
+
+	if p, ok := m["pattern"]; ok {
+		s, ok := p.(string)
+		if !ok {
+			return kw.invalid("pattern", "must be a string")
+		}
+
+		re, err := regexp.Compile(s)
+		if err != nil {
+			return kw.invalid("pattern", err.Error())
+		}
+		n.pattern = re
+	}
+
+	if u, ok := m["uniqueItems"]; ok {
+		b, ok := u.(bool)
+		if !ok {
+			return kw.invalid("uniqueItems", "must be a boolean")
+		}
+		n.uniqueItems = b
+	}
+
+	if r, ok := m["required"]; ok {
+		names, err := kw.strings("required", r)
+		if err != nil {
+			return err
+		}
+		n.required = names
+	}
+
+	if dr, ok := m["dependentRequired"]; ok {
+		deps, ok := asMap(dr)
+		if !ok {
+			return kw.invalid("dependentRequired", "must be an object")
+		}
+
+		n.dependentRequired = make(map[string][]string, len(deps))
+		for k, v := range deps {
+			names, err := kw.strings("dependentRequired", v)
+			if err != nil {
+				return err
+			}
+			n.dependentRequired[k] = names
+		}
+	}
+
+	if pp, ok := m["patternProperties"]; ok {
+		patterns, ok := asMap(pp)
+		if !ok {
+			return kw.invalid("patternProperties", "must be an object")
+		}
+
+		for _, k := range sortedKeys(patterns) {
+			re, err := regexp.Compile(k)
+			if err != nil {
+				return kw.invalid("patternProperties", err.Error())
+			}
+
+			sub, err := c.compile(n.ptr + "/patternProperties/" + escape(k))
+			if err != nil {
+				return err
+			}
+			n.patternProperties = append(n.patternProperties, patternProperty{re: re, schema: sub})
+		}
+	}
+
+	for _, num := range []struct {
+		name string
+		dst  **float64
+	}{
+		{"minimum", &n.minimum},
+		{"maximum", &n.maximum},
+		{"exclusiveMinimum", &n.exclusiveMinimum},
+		{"exclusiveMaximum", &n.exclusiveMaximum},
+		{"multipleOf", &n.multipleOf},
+	} {
+		if err := kw.number(num.name, num.dst); err != nil {
+			return err
+		}
+	}
+
+	for _, count := range []struct {
+		name string
+		dst  **int
+	}{
+		{"minLength", &n.minLength},
+		{"maxLength", &n.maxLength},
+		{"minItems", &n.minItems},
+		{"maxItems", &n.maxItems},
+		{"minContains", &n.minContains},
+		{"maxContains", &n.maxContains},
+		{"minProperties", &n.minProperties},
+		{"maxProperties", &n.maxProperties},
+	} {
+		if err := kw.count(count.name, count.dst); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range []struct {
+		name string
+		dst  **node
+	}{
+		{"items", &n.items},
+		{"contains", &n.contains},
+		{"unevaluatedItems", &n.unevaluatedItems},
+		{"additionalProperties", &n.additionalProperties},
+		{"unevaluatedProperties", &n.unevaluatedProperties},
+		{"propertyNames", &n.propertyNames},
+		{"not", &n.not},
+		{"if", &n.ifSchema},
+		{"then", &n.thenSchema},
+		{"else", &n.elseSchema},
+	} {
+		if err := kw.schema(sub.name, sub.dst); err != nil {
+			return err
+		}
+	}
+
+	for _, list := range []struct {
+		name string
+		dst  *[]*node
+	}{
+		{"prefixItems", &n.prefixItems},
+		{"allOf", &n.allOf},
+		{"anyOf", &n.anyOf},
+		{"oneOf", &n.oneOf},
+	} {
+		if err := kw.schemaList(list.name, list.dst); err != nil {
+			return err
+		}
+	}
+
+	for _, schemas := range []struct {
+		name string
+		dst  *map[string]*node
+	}{
+		{"properties", &n.properties},
+		{"dependentSchemas", &n.dependentSchemas},
+	} {
+		if err := kw.schemaMap(schemas.name, schemas.dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// keywords compiles the keywords of one schema object
+type keywords struct {
+	c *compiler
+	n *node
+	m map[string]interface{}
+}
+
+func (k keywords) invalid(keyword, message string) error {
+	return CompileError{SchemaPath: k.n.ptr + "/" + escape(keyword), Message: keyword + " " + message}
+}
+
+func (k keywords) number(keyword string, dst **float64) error {
+	v, ok := k.m[keyword]
+	if !ok {
+		return nil
+	}
+
+	f, ok := number.Float64(v)
+	if !ok {
+		return k.invalid(keyword, "must be a number")
+	}
+
+	*dst = &f
+	return nil
+}
+
+func (k keywords) count(keyword string, dst **int) error {
+	v, ok := k.m[keyword]
+	if !ok {
+		return nil
+	}
+
+	f, ok := number.Float64(v)
+	if !ok || f < 0 || f != float64(int(f)) {
+		return k.invalid(keyword, "must be a non-negative integer")
+	}
+
+	i := int(f)
+	*dst = &i
+	return nil
+}
+
+func (k keywords) strings(keyword string, v interface{}) ([]string, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		return nil, k.invalid(keyword, "must be an array of strings")
+	}
+
+	names := make([]string, len(list))
+	for i, elem := range list {
+		s, ok := elem.(string)
+		if !ok {
+			return nil, k.invalid(keyword, "must be an array of strings")
+		}
+		names[i] = s
+	}
+
+	return names, nil
+}
+
+func (k keywords) schema(keyword string, dst **node) error {
+	if _, ok := k.m[keyword]; !ok {
+		return nil
+	}
+
+	sub, err := k.c.compile(k.n.ptr + "/" + escape(keyword))
+	if err != nil {
+		return err
+	}
+
+	*dst = sub
+	return nil
+}
+
+func (k keywords) schemaList(keyword string, dst *[]*node) error {
+	v, ok := k.m[keyword]
+	if !ok {
+		return nil
+	}
+
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return k.invalid(keyword, "must be a non-empty array of schemas")
+	}
+
+	for i := range list {
+		sub, err := k.c.compile(k.n.ptr + "/" + escape(keyword) + "/" + strconv.Itoa(i))
+		if err != nil {
+			return err
+		}
+		*dst = append(*dst, sub)
+	}
+
+	return nil
+}
+
+func (k keywords) schemaMap(keyword string, dst *map[string]*node) error {
+	v, ok := k.m[keyword]
+	if !ok {
+		return nil
+	}
+
+	schemas, ok := asMap(v)
+	if !ok {
+		return k.invalid(keyword, "must be an object of schemas")
+	}
+
+	*dst = make(map[string]*node, len(schemas))
+	for name := range schemas {
+		sub, err := k.c.compile(k.n.ptr + "/" + escape(keyword) + "/" + escape(name))
+		if err != nil {
+			return err
+		}
+		(*dst)[name] = sub
+	}
+
+	return nil
+}
+
+// resolveRef turns a $ref into a JSON Pointer within the document
+func (c *compiler) resolveRef(ref string) (string, error) {
+	if c.id != "" && strings.HasPrefix(ref, c.id) {
+		ref = ref[len(c.id):]
+	}
+
+	if !strings.HasPrefix(ref, "#") {
+		return "", fmt.Errorf("%q is not a local reference; only references within the schema are supported", ref)
+	}
+
+	fragment, err := url.PathUnescape(ref[1:])
+	if err != nil {
+		return "", err
+	}
+
+	if fragment == "" || strings.HasPrefix(fragment, "/") {
+		return fragment, nil
+	}
+
+	ptr, ok := c.anchors[fragment]
+	if !ok {
+		return "", fmt.Errorf("no $anchor named %q", fragment)
+	}
+
+	return ptr, nil
+}
+
+// findAnchors records where every $anchor in the document is
+func (c *compiler) findAnchors(v interface{}, ptr string) {
+	switch val := v.(type) {
+	case []interface{}:
+		for i, elem := range val {
+			c.findAnchors(elem, ptr+"/"+strconv.Itoa(i))
+		}
+	default:
+		m, ok := asMap(v)
+		if !ok {
+			return
+		}
+
+		if anchor, ok := m["$anchor"].(string); ok {
+			c.anchors[anchor] = ptr
+		}
+
+		for k, elem := range m {
+			// enum and const hold values, not schemas
+			if k == "enum" || k == "const" {
+				continue
+			}
+			c.findAnchors(elem, ptr+"/"+escape(k))
+		}
+	}
+}
+
+// resolvePointer finds the value a JSON Pointer points to
+func resolvePointer(doc interface{}, ptr string) (interface{}, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+
+	v := doc
+	for _, token := range strings.Split(ptr[1:], "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		if m, ok := asMap(v); ok {
+			next, ok := m[token]
+			if !ok {
+				return nil, fmt.Errorf("%q does not exist in the schema", ptr)
+			}
+			v = next
+			continue
+		}
+
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%q does not exist in the schema", ptr)
+		}
+
+		i, err := strconv.Atoi(token)
+		if err != nil || i < 0 || i >= len(list) {
+			return nil, fmt.Errorf("%q does not exist in the schema", ptr)
+		}
+		v = list[i]
+	}
+
+	return v, nil
+}
+
+// escape escapes a key for use as a JSON Pointer reference token
+func escape(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case jsont.Object:
+		return m, true
+	}
+
+	return nil, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
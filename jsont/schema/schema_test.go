@@ -0,0 +1,209 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+	"github.com/dedwardstech/test/jsont"
+)
+
+const userSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "required": ["id", "name", "email"],
+  "properties": {
+    "id": {"type": "string", "format": "uuid"},
+    "name": {"type": "string", "minLength": 1, "maxLength": 10},
+    "email": {"type": "string", "format": "email"},
+    "age": {"type": "integer", "minimum": 0, "exclusiveMaximum": 150},
+    "created": {"type": "string", "format": "date-time"},
+    "roles": {
+      "type": "array",
+      "items": {"enum": ["admin", "dev", "ops"]},
+      "uniqueItems": true,
+      "minItems": 1
+    },
+    "manager": {"$ref": "#"},
+    "address": {"$ref": "#/$defs/address"},
+    "contact": {
+      "oneOf": [
+        {"type": "object", "required": ["phone"]},
+        {"type": "object", "required": ["pager"]}
+      ]
+    }
+  },
+  "patternProperties": {
+    "^x-": {"type": "string"}
+  },
+  "allOf": [
+    {"properties": {"status": {"const": "active"}}}
+  ],
+  "unevaluatedProperties": false,
+  "$defs": {
+    "address": {
+      "$anchor": "address",
+      "type": "object",
+      "properties": {
+        "zip": {"type": "string", "pattern": "^[0-9]{5}$"}
+      },
+      "additionalProperties": false
+    }
+  }
+}`
+
+func compileTestSchema(t *testing.T, raw string) *Schema {
+	doc, err := jsont.Unmarshal([]byte(raw))
+	if err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	s, err := Compile(doc)
+	if err != nil {
+		t.Fatalf("failed to compile schema: %v", err)
+	}
+
+	return s
+}
+
+func TestSchema_Validate(tt *testing.T) {
+	s := compileTestSchema(tt, userSchema)
+
+	testcases := []struct {
+		name, payload string
+		err           error
+	}{
+		{
+			name: "accepts a valid instance",
+			payload: `{
+				"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10",
+				"name": "bob",
+				"email": "bob@example.com",
+				"age": 42,
+				"created": "2021-01-30T12:00:00Z",
+				"roles": ["admin", "dev"],
+				"address": {"zip": "12345"},
+				"contact": {"phone": "555"},
+				"status": "active",
+				"x-trace": "abc",
+				"manager": {"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a11", "name": "al", "email": "al@example.com"}
+			}`,
+			err: nil,
+		},
+		{
+			name:    "reports missing required properties",
+			payload: `{"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10"}`,
+			err:     errors.New("(root): required: missing property \"name\"\n(root): required: missing property \"email\""),
+		},
+		{
+			name: "reports type, format and range violations with instance pointers",
+			payload: `{
+				"id": "nope",
+				"name": "",
+				"email": "bob",
+				"age": 150.5,
+				"created": "yesterday"
+			}`,
+			err: errors.New(`/age: type: wanted integer; got number
+/age: exclusiveMaximum: 150.5 is not less than 150
+/created: format: "yesterday" is not a valid date-time
+/email: format: "bob" is not a valid email
+/id: format: "nope" is not a valid uuid
+/name: minLength: length 0 is less than 1`),
+		},
+		{
+			name: "reports array violations",
+			payload: `{
+				"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10", "name": "bob", "email": "bob@example.com",
+				"roles": ["admin", "root", "admin"]
+			}`,
+			err: errors.New("/roles: uniqueItems: items 0 and 2 are equal\n/roles/1: enum: \"root\" is not one of the allowed values"),
+		},
+		{
+			name: "follows $refs, including recursive ones",
+			payload: `{
+				"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10", "name": "bob", "email": "bob@example.com",
+				"address": {"zip": "1234", "city": "x"},
+				"manager": {"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a11", "name": "al"}
+			}`,
+			err: errors.New(`/address/city: additionalProperties: value is not allowed
+/address/zip: pattern: "1234" does not match ^[0-9]{5}$
+/manager: required: missing property "email"`),
+		},
+		{
+			name: "checks oneOf and allOf, dropping annotations from failed subschemas",
+			payload: `{
+				"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10", "name": "bob", "email": "bob@example.com",
+				"contact": {"phone": "1", "pager": "2"},
+				"status": "gone"
+			}`,
+			err: errors.New(`/contact: oneOf: matches 2 schemas; wanted exactly one
+/status: const: wanted "active"; got "gone"
+/status: unevaluatedProperties: value is not allowed`),
+		},
+		{
+			name: "rejects properties nothing evaluated",
+			payload: `{
+				"id": "0b0e4e8c-3c2e-4f7a-9d43-3d4f1c1b2a10", "name": "bob", "email": "bob@example.com",
+				"x-ok": "fine", "extra": 1
+			}`,
+			err: errors.New("/extra: unevaluatedProperties: value is not allowed"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj, err := jsont.Unmarshal([]byte(tc.payload))
+			if err != nil {
+				t.Fatalf("failed to parse payload: %v", err)
+			}
+
+			err = s.Validate(obj)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
+
+func TestCompile(tt *testing.T) {
+	testcases := []struct {
+		name, schema string
+		err          error
+	}{
+		{
+			name:   "resolves $anchor references",
+			schema: `{"$ref": "#thing", "$defs": {"thing": {"$anchor": "thing", "type": "string"}}}`,
+			err:    nil,
+		},
+		{
+			name:   "rejects remote references",
+			schema: `{"$ref": "https://example.com/user.json"}`,
+			err:    errors.New(`invalid schema at "/$ref": $ref "https://example.com/user.json" is not a local reference; only references within the schema are supported`),
+		},
+		{
+			name:   "rejects references that don't resolve",
+			schema: `{"properties": {"a": {"$ref": "#/$defs/missing"}}}`,
+			err:    errors.New(`invalid schema at "/$defs/missing": "/$defs/missing" does not exist in the schema`),
+		},
+		{
+			name:   "rejects invalid keyword values",
+			schema: `{"minLength": -1}`,
+			err:    errors.New(`invalid schema at "/minLength": minLength must be a non-negative integer`),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			doc, err := jsont.Unmarshal([]byte(tc.schema))
+			if err != nil {
+				t.Fatalf("failed to parse schema: %v", err)
+			}
+
+			_, err = Compile(doc)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,536 @@
+package schema
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// annotations track which properties and items of an instance have been
+// evaluated, for unevaluatedProperties and unevaluatedItems.
+type annotations struct {
+	props    map[string]bool
+	items    map[int]bool
+	allItems bool
+}
+
+func (a *annotations) merge(b annotations) {
+	for k := range b.props {
+		a.evalProp(k)
+	}
+
+	for i := range b.items {
+		a.evalItem(i)
+	}
+
+	a.allItems = a.allItems || b.allItems
+}
+
+func (a *annotations) evalProp(name string) {
+	if a.props == nil {
+		a.props = make(map[string]bool)
+	}
+	a.props[name] = true
+}
+
+func (a *annotations) evalItem(i int) {
+	if a.items == nil {
+		a.items = make(map[int]bool)
+	}
+	a.items[i] = true
+}
+
+// evaluation validates one instance against one schema object
+type evaluation struct {
+	n          *node
+	ip         string
+	violations []Violation
+	ann        annotations
+}
+
+func (e *evaluation) fail(keyword, format string, args ...interface{}) {
+	e.violations = append(e.violations, Violation{
+		InstancePath: e.ip,
+		Keyword:      keyword,
+		SchemaPath:   e.n.ptr + "/" + keyword,
+		Message:      fmt.Sprintf(format, args...),
+	})
+}
+
+// inPlace applies a subschema to the same instance. Its annotations only
+// count if it passed.
+func (e *evaluation) inPlace(sub *node, v interface{}, keyword string) bool {
+	violations, ann := sub.validate(v, e.ip, keyword)
+	if len(violations) > 0 {
+		e.violations = append(e.violations, violations...)
+		return false
+	}
+
+	e.ann.merge(ann)
+	return true
+}
+
+// child applies a subschema to a property or item of the instance
+func (e *evaluation) child(sub *node, v interface{}, ip, keyword string) {
+	violations, _ := sub.validate(v, ip, keyword)
+	e.violations = append(e.violations, violations...)
+}
+
+// validate validates v, found at the instance pointer ip, against the
+// node. keyword names the applicator that led here, which is what a
+// false schema reports as having failed.
+func (n *node) validate(v interface{}, ip, keyword string) ([]Violation, annotations) {
+	if n.boolean != nil {
+		if *n.boolean {
+			return nil, annotations{}
+		}
+
+		return []Violation{{
+			InstancePath: ip,
+			Keyword:      keyword,
+			SchemaPath:   n.ptr,
+			Message:      "value is not allowed",
+		}}, annotations{}
+	}
+
+	e := &evaluation{n: n, ip: ip}
+
+	if n.ref != nil {
+		e.inPlace(n.ref, v, "$ref")
+	}
+
+	e.validateGeneric(v)
+
+	if f, ok := number.Float64(v); ok {
+		e.validateNumber(f)
+	}
+
+	if s, ok := v.(string); ok {
+		e.validateString(s)
+	}
+
+	if list, ok := v.([]interface{}); ok {
+		e.validateArray(list)
+	}
+
+	if m, ok := asMap(v); ok {
+		e.validateObject(m)
+	}
+
+	e.validateCombinators(v)
+
+	// unevaluated* see the annotations of every other keyword, so they go last
+	if list, ok := v.([]interface{}); ok && n.unevaluatedItems != nil {
+		for i, item := range list {
+			if !e.ann.allItems && !e.ann.items[i] {
+				e.child(n.unevaluatedItems, item, ip+"/"+strconv.Itoa(i), "unevaluatedItems")
+			}
+		}
+		e.ann.allItems = true
+	}
+
+	if m, ok := asMap(v); ok && n.unevaluatedProperties != nil {
+		for _, k := range sortedKeys(m) {
+			if !e.ann.props[k] {
+				e.child(n.unevaluatedProperties, m[k], ip+"/"+escape(k), "unevaluatedProperties")
+				e.ann.evalProp(k)
+			}
+		}
+	}
+
+	return e.violations, e.ann
+}
+
+func (e *evaluation) validateGeneric(v interface{}) {
+	n := e.n
+
+	if len(n.types) > 0 {
+		ok := false
+		for _, t := range n.types {
+			if hasType(v, t) {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			e.fail("type", "wanted %s; got %s", joinTypes(n.types), typeOf(v))
+		}
+	}
+
+	if n.enum != nil {
+		ok := false
+		for _, allowed := range n.enum {
+			if equal(allowed, v) {
+				ok = true
+				break
+			}
+		}
+
+		if !ok {
+			e.fail("enum", "%s is not one of the allowed values", describe(v))
+		}
+	}
+
+	if len(n.consts) > 0 && !equal(n.consts[0], v) {
+		e.fail("const", "wanted %s; got %s", describe(n.consts[0]), describe(v))
+	}
+}
+
+func (e *evaluation) validateNumber(f float64) {
+	n := e.n
+
+	if n.minimum != nil && f < *n.minimum {
+		e.fail("minimum", "%v is less than %v", f, *n.minimum)
+	}
+
+	if n.maximum != nil && f > *n.maximum {
+		e.fail("maximum", "%v is greater than %v", f, *n.maximum)
+	}
+
+	if n.exclusiveMinimum != nil && f <= *n.exclusiveMinimum {
+		e.fail("exclusiveMinimum", "%v is not greater than %v", f, *n.exclusiveMinimum)
+	}
+
+	if n.exclusiveMaximum != nil && f >= *n.exclusiveMaximum {
+		e.fail("exclusiveMaximum", "%v is not less than %v", f, *n.exclusiveMaximum)
+	}
+
+	if n.multipleOf != nil && !isMultiple(f, *n.multipleOf) {
+		e.fail("multipleOf", "%v is not a multiple of %v", f, *n.multipleOf)
+	}
+}
+
+func (e *evaluation) validateString(s string) {
+	n := e.n
+	length := utf8.RuneCountInString(s)
+
+	if n.minLength != nil && length < *n.minLength {
+		e.fail("minLength", "length %d is less than %d", length, *n.minLength)
+	}
+
+	if n.maxLength != nil && length > *n.maxLength {
+		e.fail("maxLength", "length %d is greater than %d", length, *n.maxLength)
+	}
+
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		e.fail("pattern", "%q does not match %s", s, n.pattern)
+	}
+
+	if check, ok := formats[n.format]; ok && !check(s) {
+		e.fail("format", "%q is not a valid %s", s, n.format)
+	}
+}
+
+func (e *evaluation) validateArray(list []interface{}) {
+	n := e.n
+
+	if n.minItems != nil && len(list) < *n.minItems {
+		e.fail("minItems", "has %d items; wanted at least %d", len(list), *n.minItems)
+	}
+
+	if n.maxItems != nil && len(list) > *n.maxItems {
+		e.fail("maxItems", "has %d items; wanted at most %d", len(list), *n.maxItems)
+	}
+
+	if n.uniqueItems {
+	unique:
+		for i := range list {
+			for j := 0; j < i; j++ {
+				if equal(list[i], list[j]) {
+					e.fail("uniqueItems", "items %d and %d are equal", j, i)
+					break unique
+				}
+			}
+		}
+	}
+
+	for i, sub := range n.prefixItems {
+		if i >= len(list) {
+			break
+		}
+
+		e.child(sub, list[i], e.ip+"/"+strconv.Itoa(i), "prefixItems")
+		e.ann.evalItem(i)
+	}
+
+	if n.items != nil {
+		for i := len(n.prefixItems); i < len(list); i++ {
+			e.child(n.items, list[i], e.ip+"/"+strconv.Itoa(i), "items")
+		}
+		e.ann.allItems = true
+	}
+
+	if n.contains != nil {
+		matched := 0
+		for i, item := range list {
+			if violations, _ := n.contains.validate(item, e.ip+"/"+strconv.Itoa(i), "contains"); len(violations) == 0 {
+				matched++
+				e.ann.evalItem(i)
+			}
+		}
+
+		min := 1
+		if n.minContains != nil {
+			min = *n.minContains
+		}
+
+		if matched < min {
+			e.fail("contains", "%d items match; wanted at least %d", matched, min)
+		}
+
+		if n.maxContains != nil && matched > *n.maxContains {
+			e.fail("maxContains", "%d items match; wanted at most %d", matched, *n.maxContains)
+		}
+	}
+}
+
+func (e *evaluation) validateObject(m map[string]interface{}) {
+	n := e.n
+
+	if n.minProperties != nil && len(m) < *n.minProperties {
+		e.fail("minProperties", "has %d properties; wanted at least %d", len(m), *n.minProperties)
+	}
+
+	if n.maxProperties != nil && len(m) > *n.maxProperties {
+		e.fail("maxProperties", "has %d properties; wanted at most %d", len(m), *n.maxProperties)
+	}
+
+	for _, name := range n.required {
+		if _, ok := m[name]; !ok {
+			e.fail("required", "missing property %q", name)
+		}
+	}
+
+	for _, name := range sortedKeys(m) {
+		for _, dep := range n.dependentRequired[name] {
+			if _, ok := m[dep]; !ok {
+				e.fail("dependentRequired", "property %q requires property %q", name, dep)
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(m) {
+		ip := e.ip + "/" + escape(name)
+
+		if n.propertyNames != nil {
+			e.child(n.propertyNames, name, ip, "propertyNames")
+		}
+
+		evaluated := false
+		if sub, ok := n.properties[name]; ok {
+			e.child(sub, m[name], ip, "properties")
+			evaluated = true
+		}
+
+		for _, pp := range n.patternProperties {
+			if pp.re.MatchString(name) {
+				e.child(pp.schema, m[name], ip, "patternProperties")
+				evaluated = true
+			}
+		}
+
+		if !evaluated && n.additionalProperties != nil {
+			e.child(n.additionalProperties, m[name], ip, "additionalProperties")
+			evaluated = true
+		}
+
+		if evaluated {
+			e.ann.evalProp(name)
+		}
+	}
+
+	for _, name := range sortedKeys(m) {
+		if sub, ok := n.dependentSchemas[name]; ok {
+			e.inPlace(sub, m, "dependentSchemas")
+		}
+	}
+}
+
+func (e *evaluation) validateCombinators(v interface{}) {
+	n := e.n
+
+	for _, sub := range n.allOf {
+		e.inPlace(sub, v, "allOf")
+	}
+
+	if len(n.anyOf) > 0 {
+		matched := 0
+		for _, sub := range n.anyOf {
+			if violations, ann := sub.validate(v, e.ip, "anyOf"); len(violations) == 0 {
+				matched++
+				e.ann.merge(ann)
+			}
+		}
+
+		if matched == 0 {
+			e.fail("anyOf", "does not match any of the %d schemas", len(n.anyOf))
+		}
+	}
+
+	if len(n.oneOf) > 0 {
+		var matched []annotations
+		for _, sub := range n.oneOf {
+			if violations, ann := sub.validate(v, e.ip, "oneOf"); len(violations) == 0 {
+				matched = append(matched, ann)
+			}
+		}
+
+		switch len(matched) {
+		case 0:
+			e.fail("oneOf", "does not match any of the %d schemas", len(n.oneOf))
+		case 1:
+			e.ann.merge(matched[0])
+		default:
+			e.fail("oneOf", "matches %d schemas; wanted exactly one", len(matched))
+		}
+	}
+
+	if n.not != nil {
+		if violations, _ := n.not.validate(v, e.ip, "not"); len(violations) == 0 {
+			e.fail("not", "must not match the schema")
+		}
+	}
+
+	if n.ifSchema != nil {
+		violations, ann := n.ifSchema.validate(v, e.ip, "if")
+		if len(violations) == 0 {
+			e.ann.merge(ann)
+			if n.thenSchema != nil {
+				e.inPlace(n.thenSchema, v, "then")
+			}
+		} else if n.elseSchema != nil {
+			e.inPlace(n.elseSchema, v, "else")
+		}
+	}
+}
+
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "object":
+		_, ok := asMap(v)
+		return ok
+	case "number":
+		_, ok := number.Float64(v)
+		return ok
+	case "integer":
+		f, ok := number.Float64(v)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	}
+
+	return false
+}
+
+func typeOf(v interface{}) string {
+	for _, t := range []string{"null", "boolean", "string", "array", "object", "integer", "number"} {
+		if hasType(v, t) {
+			return t
+		}
+	}
+
+	return fmt.Sprintf("%T", v)
+}
+
+func joinTypes(types []string) string {
+	if len(types) == 1 {
+		return types[0]
+	}
+
+	s := ""
+	for i, t := range types {
+		switch {
+		case i == len(types)-1:
+			s += " or "
+		case i > 0:
+			s += ", "
+		}
+		s += t
+	}
+
+	return s
+}
+
+// isMultiple checks divisibility using the shortest decimal form of each
+// number, so 0.3 is a multiple of 0.1 despite floating point.
+func isMultiple(f, of float64) bool {
+	x, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if !ok {
+		return false
+	}
+
+	y, ok := new(big.Rat).SetString(strconv.FormatFloat(of, 'g', -1, 64))
+	if !ok || y.Sign() == 0 {
+		return false
+	}
+
+	return new(big.Rat).Quo(x, y).IsInt()
+}
+
+// equal compares JSON values the way the spec does: numbers by value and
+// objects regardless of key order.
+func equal(a, b interface{}) bool {
+	if x, ok := number.Float64(a); ok {
+		y, ok := number.Float64(b)
+		return ok && x == y
+	}
+
+	if am, ok := asMap(a); ok {
+		bm, ok := asMap(b)
+		if !ok || len(am) != len(bm) {
+			return false
+		}
+
+		for k, av := range am {
+			bv, ok := bm[k]
+			if !ok || !equal(av, bv) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if as, ok := a.([]interface{}); ok {
+		bs, ok := b.([]interface{})
+		if !ok || len(as) != len(bs) {
+			return false
+		}
+
+		for i := range as {
+			if !equal(as[i], bs[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func describe(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(val)
+	}
+
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,156 @@
+package jsont
+
+import (
+	"math"
+
+	"github.com/dedwardstech/test/internal/number"
+)
+
+// maxEnumValues is the most distinct values a field can have and still be
+// described as an enum.
+const maxEnumValues = 5
+
+// shape merges every value seen at one place across a set of samples.
+// It's what InferSchema and GenerateStructs build their output from.
+type shape struct {
+	// seen counts the values found here, one per sample or array element
+	seen  int
+	types map[string]bool
+
+	// objects counts the values that were objects, so a property seen in
+	// every one of them can be called required
+	objects int
+	props   map[string]*shape
+
+	items *shape
+
+	// scalars are the distinct strings and integers seen, until there are
+	// too many of them to be an enum, and counts how often each was seen
+	scalars []interface{}
+	counts  []int
+	tooMany bool
+}
+
+func newShape() *shape {
+	return &shape{types: make(map[string]bool)}
+}
+
+func (s *shape) add(v interface{}) {
+	s.seen++
+
+	if m, ok := asMap(v); ok {
+		s.types["object"] = true
+		s.objects++
+
+		if s.props == nil {
+			s.props = make(map[string]*shape)
+		}
+
+		for k, pv := range m {
+			p, ok := s.props[k]
+			if !ok {
+				p = newShape()
+				s.props[k] = p
+			}
+			p.add(pv)
+		}
+
+		return
+	}
+
+	if f, ok := number.Float64(v); ok {
+		if f == math.Trunc(f) && !math.IsInf(f, 0) {
+			s.types["integer"] = true
+			s.addScalar(f)
+		} else {
+			s.types["number"] = true
+		}
+
+		return
+	}
+
+	switch val := v.(type) {
+	case nil:
+		s.types["null"] = true
+	case bool:
+		s.types["boolean"] = true
+	case string:
+		s.types["string"] = true
+		s.addScalar(val)
+	case []interface{}:
+		s.types["array"] = true
+
+		if s.items == nil {
+			s.items = newShape()
+		}
+		for _, elem := range val {
+			s.items.add(elem)
+		}
+	}
+}
+
+func (s *shape) addScalar(v interface{}) {
+	if s.tooMany {
+		return
+	}
+
+	for i, seen := range s.scalars {
+		if seen == v {
+			s.counts[i]++
+			return
+		}
+	}
+
+	if len(s.scalars) == maxEnumValues {
+		s.tooMany, s.scalars, s.counts = true, nil, nil
+		return
+	}
+
+	s.scalars = append(s.scalars, v)
+	s.counts = append(s.counts, 1)
+}
+
+// required reports whether a property was in every object seen
+func (s *shape) required(prop string) bool {
+	p, ok := s.props[prop]
+	return ok && p.seen >= s.objects
+}
+
+// isEnum reports whether the values seen look like a fixed set. Every
+// value has to repeat, otherwise every field in a single sample would be
+// an enum, and so would IDs that happened to share a sample with one
+// repeated value.
+func (s *shape) isEnum() bool {
+	if s.tooMany || len(s.scalars) == 0 {
+		return false
+	}
+
+	for _, n := range s.counts {
+		if n < 2 {
+			return false
+		}
+	}
+
+	// mixing scalars with objects or arrays isn't an enum
+	for t := range s.types {
+		if t != "string" && t != "integer" && t != "null" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// typeNames lists the JSON Schema types seen, with integer folded into
+// number when both were seen.
+func (s *shape) typeNames() []string {
+	var names []string
+	for _, t := range []string{"null", "boolean", "integer", "number", "string", "array", "object"} {
+		if !s.types[t] || (t == "integer" && s.types["number"]) {
+			continue
+		}
+		names = append(names, t)
+	}
+
+	return names
+}
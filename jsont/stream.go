@@ -0,0 +1,276 @@
+package jsont
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotAnObject indicates a JSON document didn't start with an object
+var ErrNotAnObject = errors.New("json document is not an object")
+
+// PathSet is a compiled set of property paths to pull out of a JSON
+// document without decoding all of it.
+type PathSet struct {
+	root  *pathTrie
+	count int
+}
+
+// pathTrie holds the paths of a PathSet, one segment per level: object
+// keys in children and array indexes in elements. A node that's wanted is
+// captured whole, along with everything under it.
+type pathTrie struct {
+	children map[string]*pathTrie
+	elements map[int]*pathTrie
+	wanted   bool
+}
+
+// CompilePaths compiles property paths, like "meta.version" or
+// "rows[0].id", into a PathSet.
+func CompilePaths(propertyPaths ...string) (*PathSet, error) {
+	ps := &PathSet{root: &pathTrie{}}
+
+	for _, p := range propertyPaths {
+		if p == "" {
+			return nil, errors.New("cannot compile an empty property path")
+		}
+
+		segments := splitPath(p)
+		if segments[0].IsIndex {
+			return nil, errors.Errorf("%s: cannot index into the root object", p)
+		}
+
+		node := ps.root
+		for _, seg := range segments {
+			node = node.child(seg)
+		}
+		node.wanted = true
+	}
+
+	ps.count = ps.root.outermost()
+
+	return ps, nil
+}
+
+// outermost counts the wanted nodes under t that aren't under another
+// wanted node. Those are the values an extraction has to find; the rest
+// come along with them.
+func (t *pathTrie) outermost() int {
+	if t.wanted {
+		return 1
+	}
+
+	n := 0
+	for _, c := range t.children {
+		n += c.outermost()
+	}
+	for _, e := range t.elements {
+		n += e.outermost()
+	}
+
+	return n
+}
+
+// child returns the node for seg, adding it if it's new
+func (t *pathTrie) child(seg PathSegment) *pathTrie {
+	if seg.IsIndex {
+		if t.elements == nil {
+			t.elements = make(map[int]*pathTrie)
+		}
+
+		if _, ok := t.elements[seg.Index]; !ok {
+			t.elements[seg.Index] = &pathTrie{}
+		}
+
+		return t.elements[seg.Index]
+	}
+
+	if t.children == nil {
+		t.children = make(map[string]*pathTrie)
+	}
+
+	if _, ok := t.children[seg.Key]; !ok {
+		t.children[seg.Key] = &pathTrie{}
+	}
+
+	return t.children[seg.Key]
+}
+
+// Extract reads a JSON document from r in a single pass and returns an
+// Object holding only the values at the compiled paths. Subtrees nobody
+// asked for are skipped over token by token instead of being decoded, so
+// memory use depends on the size of the values wanted, not the document.
+//
+// Paths missing from the document are missing from the Object, so the
+// usual getters report them with ErrPropertyDoesNotExist. Arrays hold the
+// elements asked for at their indexes, with null in place of any elements
+// before them that weren't asked for. Reading stops as
+// soon as every path has been found; the rest of r is never read, or
+// checked for errors.
+func (ps *PathSet) Extract(r io.Reader) (Object, error) {
+	dec := json.NewDecoder(r)
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, ErrNotAnObject
+	}
+
+	e := &extraction{dec: dec, remaining: ps.count, found: make(map[*pathTrie]bool)}
+	obj := make(map[string]interface{})
+	if err := e.object(ps.root, obj); err != nil && err != errExtracted {
+		return nil, err
+	}
+
+	return Set(obj), nil
+}
+
+// errExtracted stops an extraction once every path has been found
+var errExtracted = errors.New("every path has been extracted")
+
+// extraction counts down the wanted nodes still to be found. A node is
+// only counted the first time it's found, since a duplicate key finds it
+// again.
+type extraction struct {
+	dec       *json.Decoder
+	remaining int
+	found     map[*pathTrie]bool
+}
+
+// object reads the members of an object whose opening brace has already
+// been read, keeping the ones node asks for in out.
+func (e *extraction) object(node *pathTrie, out map[string]interface{}) error {
+	for e.dec.More() {
+		tok, err := e.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		key, _ := tok.(string)
+		v, found, err := e.value(node.children[key])
+		if found {
+			out[key] = v
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	// the closing brace
+	_, err := e.dec.Token()
+	return err
+}
+
+// array reads the elements of an array whose opening bracket has already
+// been read, keeping the ones node asks for at their indexes.
+func (e *extraction) array(node *pathTrie) ([]interface{}, error) {
+	var out []interface{}
+
+	for i := 0; e.dec.More(); i++ {
+		v, found, err := e.value(node.elements[i])
+		if found {
+			for len(out) < i {
+				out = append(out, nil)
+			}
+			out = append(out, v)
+		}
+
+		if err != nil {
+			return out, err
+		}
+	}
+
+	// the closing bracket
+	_, err := e.dec.Token()
+	return out, err
+}
+
+// value reads the next value for node: all of it if node is wanted, only
+// the parts node leads to if not, and none of it if node is nil. It
+// reports whether anything was kept.
+func (e *extraction) value(node *pathTrie) (interface{}, bool, error) {
+	switch {
+	case node == nil:
+		return nil, false, e.skip()
+	case node.wanted:
+		var v interface{}
+		if err := e.dec.Decode(&v); err != nil {
+			return nil, false, err
+		}
+
+		if e.found[node] {
+			return v, true, nil
+		}
+
+		e.found[node] = true
+		if e.remaining--; e.remaining == 0 {
+			return v, true, errExtracted
+		}
+
+		return v, true, nil
+	}
+
+	return e.descend(node)
+}
+
+// descend follows the paths under node into the next value. Only objects
+// and arrays can be followed; anything else is skipped.
+func (e *extraction) descend(node *pathTrie) (interface{}, bool, error) {
+	tok, err := e.dec.Token()
+	if err != nil {
+		return nil, false, err
+	}
+
+	d, ok := tok.(json.Delim)
+	switch {
+	case ok && d == '{':
+		sub := make(map[string]interface{})
+		err := e.object(node, sub)
+		return sub, len(sub) > 0, err
+	case ok && d == '[':
+		sub, err := e.array(node)
+		return sub, len(sub) > 0, err
+	}
+
+	return nil, false, nil
+}
+
+// skip reads past the next value without decoding it
+func (e *extraction) skip() error {
+	tok, err := e.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	if d, ok := tok.(json.Delim); ok && (d == '{' || d == '[') {
+		return e.skipRest(1)
+	}
+
+	return nil
+}
+
+// skipRest reads until depth open objects and arrays have been closed
+func (e *extraction) skipRest(depth int) error {
+	for depth > 0 {
+		tok, err := e.dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				depth++
+			default:
+				depth--
+			}
+		}
+	}
+
+	return nil
+}
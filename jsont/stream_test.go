@@ -0,0 +1,164 @@
+package jsont
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestPathSet_Extract(tt *testing.T) {
+	const export = `{
+		"meta": {"version": 2, "exported": "2021-01-30", "source": {"host": "db1"}},
+		"rows": [{"id": 1}, {"id": 2}, [[{"deep": true}]]],
+		"summary": {"count": 3, "ok": true, "tags": ["a", "b"]},
+		"owner": "bob"
+	}`
+
+	testcases := []struct {
+		name     string
+		paths    []string
+		input    string
+		expected Object
+		err      error
+	}{
+		{
+			name:  "extracts only the requested values",
+			paths: []string{"meta.version", "summary.tags", "owner"},
+			input: export,
+			expected: Object{
+				"meta":    map[string]interface{}{"version": float64(2)},
+				"summary": map[string]interface{}{"tags": []interface{}{"a", "b"}},
+				"owner":   "bob",
+			},
+		},
+		{
+			name:  "captures whole subtrees",
+			paths: []string{"meta.source", "meta"},
+			input: export,
+			expected: Object{
+				"meta": map[string]interface{}{
+					"version":  float64(2),
+					"exported": "2021-01-30",
+					"source":   map[string]interface{}{"host": "db1"},
+				},
+			},
+		},
+		{
+			name:     "leaves out paths that aren't in the document",
+			paths:    []string{"meta.missing", "rows.id", "summary.count"},
+			input:    export,
+			expected: Object{"summary": map[string]interface{}{"count": float64(3)}},
+		},
+		{
+			name:  "follows array indexes",
+			paths: []string{"rows[1].id", "rows[2][0][0].deep", "summary.tags[1]", "rows[9]"},
+			input: export,
+			expected: Object{
+				"rows": []interface{}{
+					nil,
+					map[string]interface{}{"id": float64(2)},
+					[]interface{}{[]interface{}{map[string]interface{}{"deep": true}}},
+				},
+				"summary": map[string]interface{}{"tags": []interface{}{nil, "b"}},
+			},
+		},
+		{
+			name:     "stops reading once every path is found",
+			paths:    []string{"a.b"},
+			input:    `{"a": {"b": 1}, "c": this is never read`,
+			expected: Object{"a": map[string]interface{}{"b": float64(1)}},
+		},
+		{
+			name:     "stops reading once a subtree holding nested paths is found",
+			paths:    []string{"a.b", "a"},
+			input:    `{"a": {"b": 1}, "c": this is never read`,
+			expected: Object{"a": map[string]interface{}{"b": float64(1)}},
+		},
+		{
+			name:     "counts duplicate keys once",
+			paths:    []string{"a", "b"},
+			input:    `{"a": 1, "a": 2, "b": 3}`,
+			expected: Object{"a": float64(2), "b": float64(3)},
+		},
+		{
+			name:  "rejects documents that aren't objects",
+			paths: []string{"a"},
+			input: `[1, 2]`,
+			err:   ErrNotAnObject,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			ps, err := CompilePaths(tc.paths...)
+			if err != nil {
+				t.Fatalf("failed to compile paths: %v", err)
+			}
+
+			obj, err := ps.Extract(strings.NewReader(tc.input))
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, obj)
+			}
+		})
+	}
+}
+
+func TestPathSet_Extract_Getters(t *testing.T) {
+	ps, err := CompilePaths("summary.count", "owner")
+	if err != nil {
+		t.Fatalf("failed to compile paths: %v", err)
+	}
+
+	obj, err := ps.Extract(strings.NewReader(`{"owner": "bob", "summary": {"count": 3}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n, err := obj.GetNumber("summary.count"); err != nil || n != 3 {
+		t.Errorf("expected summary.count to be 3, got %v (%v)", n, err)
+	}
+
+	if _, err := obj.GetStr("meta.version"); err != ErrPropertyDoesNotExist {
+		t.Errorf("expected ErrPropertyDoesNotExist, got %v", err)
+	}
+}
+
+func TestCompilePaths(tt *testing.T) {
+	testcases := []struct {
+		name  string
+		paths []string
+		err   error
+	}{
+		{
+			name:  "compiles keys and indexes",
+			paths: []string{"rows[0].id", `labels["app.name"]`},
+		},
+		{
+			name:  "rejects empty paths",
+			paths: []string{""},
+			err:   errors.New("cannot compile an empty property path"),
+		},
+		{
+			name:  "rejects indexes into the root object",
+			paths: []string{"[0].id"},
+			err:   errors.New("[0].id: cannot index into the root object"),
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			_, err := CompilePaths(tc.paths...)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+			}
+		})
+	}
+}
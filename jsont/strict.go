@@ -0,0 +1,233 @@
+package jsont
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dedwardstech/test/internal/path"
+)
+
+// StrictOption turns on an extra check in UnmarshalStrict
+type StrictOption func(*strictConfig)
+
+type strictConfig struct {
+	utf8    bool
+	numbers bool
+}
+
+// RejectInvalidUTF8 reports strings and keys that aren't valid UTF-8.
+// encoding/json quietly replaces the bad bytes with U+FFFD.
+func RejectInvalidUTF8() StrictOption {
+	return func(c *strictConfig) {
+		c.utf8 = true
+	}
+}
+
+// RejectLossyNumbers reports numbers that can't be held in a float64
+// without losing precision, like 9007199254740993. Numbers that overflow a
+// float64 are always reported.
+func RejectLossyNumbers() StrictOption {
+	return func(c *strictConfig) {
+		c.numbers = true
+	}
+}
+
+// StrictViolation is one problem UnmarshalStrict found in a payload. Offset
+// is the byte offset of the offending token.
+type StrictViolation struct {
+	Path   string
+	Offset int64
+	Reason string
+}
+
+func (v StrictViolation) String() string {
+	p := v.Path
+	if p == "" {
+		p = "(root)"
+	}
+
+	return fmt.Sprintf("%s: %s (offset %d)", p, v.Reason, v.Offset)
+}
+
+// StrictError holds every StrictViolation found in a payload
+type StrictError []StrictViolation
+
+func (e StrictError) Error() string {
+	lines := make([]string, len(e))
+	for i, v := range e {
+		lines[i] = v.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// UnmarshalStrict unmarshals a JSON payload like Unmarshal, but reports
+// duplicate keys instead of keeping the last one, and any data after the
+// document. Opts turn on more checks. Every problem found is returned in a
+// StrictError; malformed JSON is returned as the decoder's error.
+func UnmarshalStrict(payload []byte, opts ...StrictOption) (Object, error) {
+	s := &strictDecoder{payload: payload}
+	for _, opt := range opts {
+		opt(&s.cfg)
+	}
+
+	s.dec = json.NewDecoder(bytes.NewReader(payload))
+	s.dec.UseNumber()
+
+	tok, start, err := s.token()
+	if err != nil {
+		return nil, err
+	}
+
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, ErrNotAnObject
+	}
+
+	v, err := s.value("", tok, start)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, start, err := s.token(); err != io.EOF {
+		s.violate("", start, "trailing data after the document")
+	}
+
+	if len(s.violations) > 0 {
+		return nil, s.violations
+	}
+
+	return Set(v.(map[string]interface{})), nil
+}
+
+type strictDecoder struct {
+	cfg        strictConfig
+	dec        *json.Decoder
+	payload    []byte
+	violations StrictError
+}
+
+// token reads the next token along with the offset it starts at
+func (s *strictDecoder) token() (json.Token, int64, error) {
+	start := s.dec.InputOffset()
+	for start < int64(len(s.payload)) && strings.IndexByte(" \t\r\n,:", s.payload[start]) >= 0 {
+		start++
+	}
+
+	tok, err := s.dec.Token()
+	return tok, start, err
+}
+
+func (s *strictDecoder) value(p string, tok json.Token, start int64) (interface{}, error) {
+	switch t := tok.(type) {
+	case json.Delim:
+		if t == '{' {
+			return s.object(p)
+		}
+
+		return s.array(p)
+	case string:
+		s.checkString(p, start, "string")
+		return t, nil
+	case json.Number:
+		return s.number(p, t, start), nil
+	}
+
+	return tok, nil
+}
+
+func (s *strictDecoder) object(p string) (interface{}, error) {
+	m := make(map[string]interface{})
+	seen := make(map[string]int64)
+
+	for s.dec.More() {
+		tok, start, err := s.token()
+		if err != nil {
+			return nil, err
+		}
+
+		key := tok.(string)
+		kp := path.Key(p, key)
+		s.checkString(kp, start, "key")
+
+		if first, ok := seen[key]; ok {
+			s.violate(kp, start, fmt.Sprintf("duplicate key, first seen at offset %d", first))
+		} else {
+			seen[key] = start
+		}
+
+		if tok, start, err = s.token(); err != nil {
+			return nil, err
+		}
+
+		if m[key], err = s.value(kp, tok, start); err != nil {
+			return nil, err
+		}
+	}
+
+	// the closing brace
+	_, _, err := s.token()
+	return m, err
+}
+
+func (s *strictDecoder) array(p string) (interface{}, error) {
+	a := make([]interface{}, 0)
+
+	for i := 0; s.dec.More(); i++ {
+		tok, start, err := s.token()
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := s.value(path.Index(p, i), tok, start)
+		if err != nil {
+			return nil, err
+		}
+
+		a = append(a, v)
+	}
+
+	// the closing bracket
+	_, _, err := s.token()
+	return a, err
+}
+
+// checkString reports a string token that isn't valid UTF-8. The decoder
+// has already replaced the bad bytes, so the raw payload is checked.
+func (s *strictDecoder) checkString(p string, start int64, what string) {
+	if s.cfg.utf8 && !utf8.Valid(s.payload[start:s.dec.InputOffset()]) {
+		s.violate(p, start, "invalid UTF-8 in "+what)
+	}
+}
+
+// number converts n to a float64, the way Unmarshal would, reporting it if
+// the conversion loses precision.
+func (s *strictDecoder) number(p string, n json.Number, start int64) float64 {
+	f, err := strconv.ParseFloat(n.String(), 64)
+	if err != nil {
+		s.violate(p, start, fmt.Sprintf("number %s overflows a float64", n))
+		return f
+	}
+
+	if !s.cfg.numbers {
+		return f
+	}
+
+	// compare the exact decimal values, so 1.0 and 1e0 aren't lossy
+	exact, _ := new(big.Rat).SetString(n.String())
+	held, _ := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	if exact.Cmp(held) != 0 {
+		s.violate(p, start, fmt.Sprintf("number %s loses precision as a float64, becoming %v", n, f))
+	}
+
+	return f
+}
+
+func (s *strictDecoder) violate(p string, offset int64, reason string) {
+	s.violations = append(s.violations, StrictViolation{Path: p, Offset: offset, Reason: reason})
+}
@@ -0,0 +1,88 @@
+package jsont
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dedwardstech/test/compare"
+)
+
+func TestUnmarshalStrict(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		payload  string
+		opts     []StrictOption
+		expected Object
+		err      error
+	}{
+		{
+			name:     "decodes payloads like Unmarshal",
+			payload:  `{"id": 1, "tags": ["a"], "owner": {"name": "bob", "admin": false}, "note": null}`,
+			expected: Object{"id": float64(1), "tags": []interface{}{"a"}, "owner": map[string]interface{}{"name": "bob", "admin": false}, "note": nil},
+		},
+		{
+			name:    "reports duplicate keys with their paths",
+			payload: `{"id": 1, "users": [{"id": 1}, {"id": 2, "id": 3}], "id": 4}`,
+			err: errors.New(`users[1].id: duplicate key, first seen at offset 32 (offset 41)
+id: duplicate key, first seen at offset 1 (offset 52)`),
+		},
+		{
+			name:    "reports trailing data",
+			payload: `{"id": 1} {"id": 2}`,
+			err:     errors.New("(root): trailing data after the document (offset 10)"),
+		},
+		{
+			name:    "reports numbers that overflow",
+			payload: `{"big": 1e400}`,
+			err:     errors.New("big: number 1e400 overflows a float64 (offset 8)"),
+		},
+		{
+			name:     "allows lossy numbers by default",
+			payload:  `{"id": 9007199254740993}`,
+			expected: Object{"id": float64(9007199254740992)},
+		},
+		{
+			name:    "rejects lossy numbers",
+			payload: `{"id": 9007199254740993, "ok": [0.1, 1.0, 25e-1, 9007199254740992]}`,
+			opts:    []StrictOption{RejectLossyNumbers()},
+			err:     errors.New("id: number 9007199254740993 loses precision as a float64, becoming 9.007199254740992e+15 (offset 7)"),
+		},
+		{
+			name:     "allows invalid UTF-8 by default",
+			payload:  "{\"name\": \"b\xffb\"}",
+			expected: Object{"name": "b�b"},
+		},
+		{
+			name:    "rejects invalid UTF-8",
+			payload: "{\"name\": \"b\xffb\", \"k\xfe\": \"ok\"}",
+			opts:    []StrictOption{RejectInvalidUTF8()},
+			err: errors.New("name: invalid UTF-8 in string (offset 9)\n" +
+				"k�: invalid UTF-8 in key (offset 16)"),
+		},
+		{
+			name:    "returns syntax errors",
+			payload: `{"id": 1,}`,
+			err:     errors.New("invalid character ',' looking for beginning of value"),
+		},
+		{
+			name:    "rejects documents that aren't objects",
+			payload: `[1]`,
+			err:     ErrNotAnObject,
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj, err := UnmarshalStrict([]byte(tc.payload), tc.opts...)
+			if testErr := compare.Errors(tc.err, err); testErr != nil {
+				t.Error(testErr)
+				return
+			}
+
+			if tc.err == nil && !reflect.DeepEqual(obj, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, obj)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package jsont
+
+// asMap unwraps the two map types a JSON object can show up as.
+func asMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case Object:
+		return m, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,126 @@
+package jsont
+
+// WalkAction tells Walk and Transform where to go next
+type WalkAction int
+
+const (
+	// Continue carries on into the value's children, then its siblings
+	Continue WalkAction = iota
+
+	// SkipSubtree carries on with the value's siblings, skipping its
+	// children
+	SkipSubtree
+
+	// Stop ends the walk
+	Stop
+)
+
+// Walk visits every value in the Object depth first, parents before their
+// children. Object keys are visited in sorted order. fn decides whether
+// the walk goes into each value's children, skips them or stops.
+//
+//	obj.Walk(func(p jsont.Path, v interface{}) jsont.WalkAction {
+//		if strings.Contains(p.Last().Key, "password") {
+//			t.Errorf("%s: payload exposes a password", p)
+//		}
+//		return jsont.Continue
+//	})
+//
+// The Path passed to fn is reused, so copy it to keep it.
+func (o Object) Walk(fn func(p Path, v interface{}) WalkAction) {
+	walk(nil, map[string]interface{}(o), fn)
+}
+
+// walk visits the children of v, returning false if the walk was stopped
+func walk(p Path, v interface{}, fn func(Path, interface{}) WalkAction) bool {
+	visit := func(child Path, cv interface{}) bool {
+		switch fn(child, cv) {
+		case Stop:
+			return false
+		case SkipSubtree:
+			return true
+		}
+
+		return walk(child, cv, fn)
+	}
+
+	if m, ok := asMap(v); ok {
+		for _, k := range sortedKeys(m) {
+			if !visit(append(p, PathSegment{Key: k}), m[k]) {
+				return false
+			}
+		}
+	} else if a, ok := v.([]interface{}); ok {
+		for i, elem := range a {
+			if !visit(append(p, PathSegment{Index: i, IsIndex: true}), elem) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Transform returns a copy of the Object with values replaced by fn. Values
+// are visited like Walk visits them, and fn returns the value to keep in
+// each one's place, along with where to go next. Children of the value fn
+// returns are visited in turn. Once fn returns Stop, the rest of the Object
+// is kept as it is. The Object itself isn't changed, and as with Walk, the
+// Path passed to fn is reused.
+//
+//	masked := obj.Transform(func(p jsont.Path, v interface{}) (interface{}, jsont.WalkAction) {
+//		if p.Last().Key == "email" {
+//			return "<email>", jsont.SkipSubtree
+//		}
+//		return v, jsont.Continue
+//	})
+func (o Object) Transform(fn func(p Path, v interface{}) (interface{}, WalkAction)) Object {
+	t := &transformer{fn: fn}
+	return Set(t.children(nil, map[string]interface{}(o)).(map[string]interface{}))
+}
+
+type transformer struct {
+	fn      func(Path, interface{}) (interface{}, WalkAction)
+	stopped bool
+}
+
+// children copies v, transforming its children
+func (t *transformer) children(p Path, v interface{}) interface{} {
+	if m, ok := asMap(v); ok {
+		out := make(map[string]interface{}, len(m))
+		for _, k := range sortedKeys(m) {
+			out[k] = t.value(append(p, PathSegment{Key: k}), m[k])
+		}
+
+		return out
+	}
+
+	if a, ok := v.([]interface{}); ok {
+		out := make([]interface{}, len(a))
+		for i, elem := range a {
+			out[i] = t.value(append(p, PathSegment{Index: i, IsIndex: true}), elem)
+		}
+
+		return out
+	}
+
+	return v
+}
+
+// value transforms v, then its children, unless the transform has stopped
+func (t *transformer) value(p Path, v interface{}) interface{} {
+	if t.stopped {
+		return v
+	}
+
+	nv, action := t.fn(p, v)
+	switch action {
+	case Stop:
+		t.stopped = true
+		return nv
+	case SkipSubtree:
+		return nv
+	}
+
+	return t.children(p, nv)
+}
@@ -0,0 +1,169 @@
+package jsont
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func walkFixture() Object {
+	return Object{
+		"id": "u1",
+		"user": map[string]interface{}{
+			"name":     "bob",
+			"password": "hunter2",
+			"emails":   []interface{}{"b@example.com", "bob@example.com"},
+		},
+		"meta": map[string]interface{}{"version": 2.0},
+	}
+}
+
+func TestObject_Walk(tt *testing.T) {
+	testcases := []struct {
+		name     string
+		action   func(p Path, v interface{}) WalkAction
+		expected []string
+	}{
+		{
+			name:   "visits every value depth first",
+			action: func(Path, interface{}) WalkAction { return Continue },
+			expected: []string{
+				"id", "meta", "meta.version", "user", "user.emails", "user.emails[0]",
+				"user.emails[1]", "user.name", "user.password",
+			},
+		},
+		{
+			name: "skips subtrees",
+			action: func(p Path, v interface{}) WalkAction {
+				if p.String() == "meta" || p.Last().Key == "emails" {
+					return SkipSubtree
+				}
+				return Continue
+			},
+			expected: []string{"id", "meta", "user", "user.emails", "user.name", "user.password"},
+		},
+		{
+			name: "stops",
+			action: func(p Path, v interface{}) WalkAction {
+				if p.Last().IsIndex {
+					return Stop
+				}
+				return Continue
+			},
+			expected: []string{"id", "meta", "meta.version", "user", "user.emails", "user.emails[0]"},
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			var visited []string
+			walkFixture().Walk(func(p Path, v interface{}) WalkAction {
+				visited = append(visited, p.String())
+				return tc.action(p, v)
+			})
+
+			if !reflect.DeepEqual(visited, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, visited)
+			}
+		})
+	}
+
+	tt.Run("audits keys", func(t *testing.T) {
+		var found []string
+		walkFixture().Walk(func(p Path, v interface{}) WalkAction {
+			if strings.Contains(p.Last().Key, "password") {
+				found = append(found, fmt.Sprintf("%s=%v", p, v))
+			}
+			return Continue
+		})
+
+		if !reflect.DeepEqual(found, []string{"user.password=hunter2"}) {
+			t.Errorf("expected to find user.password, got %v", found)
+		}
+	})
+}
+
+func TestObject_Transform(tt *testing.T) {
+	testcases := []struct {
+		name      string
+		transform func(p Path, v interface{}) (interface{}, WalkAction)
+		expected  Object
+	}{
+		{
+			name: "replaces values",
+			transform: func(p Path, v interface{}) (interface{}, WalkAction) {
+				if s, ok := v.(string); ok && strings.Contains(s, "@") {
+					return "<email>", Continue
+				}
+				if p.Last().Key == "password" {
+					return "<redacted>", Continue
+				}
+				return v, Continue
+			},
+			expected: Object{
+				"id": "u1",
+				"user": map[string]interface{}{
+					"name":     "bob",
+					"password": "<redacted>",
+					"emails":   []interface{}{"<email>", "<email>"},
+				},
+				"meta": map[string]interface{}{"version": 2.0},
+			},
+		},
+		{
+			name: "visits the children of replacements",
+			transform: func(p Path, v interface{}) (interface{}, WalkAction) {
+				if p.String() == "meta" {
+					return map[string]interface{}{"version": "v"}, Continue
+				}
+				if p.String() == "meta.version" {
+					return v.(string) + "3", Continue
+				}
+				return v, Continue
+			},
+			expected: Object{
+				"id": "u1",
+				"user": map[string]interface{}{
+					"name":     "bob",
+					"password": "hunter2",
+					"emails":   []interface{}{"b@example.com", "bob@example.com"},
+				},
+				"meta": map[string]interface{}{"version": "v3"},
+			},
+		},
+		{
+			name: "keeps the rest once stopped",
+			transform: func(p Path, v interface{}) (interface{}, WalkAction) {
+				if p.String() == "meta" {
+					return nil, Stop
+				}
+				return "x", SkipSubtree
+			},
+			expected: Object{
+				"id":   "x",
+				"meta": nil,
+				"user": map[string]interface{}{
+					"name":     "bob",
+					"password": "hunter2",
+					"emails":   []interface{}{"b@example.com", "bob@example.com"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		tt.Run(tc.name, func(t *testing.T) {
+			obj := walkFixture()
+			got := obj.Transform(tc.transform)
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("expected: %v\ngot: %v", tc.expected, got)
+			}
+
+			if !reflect.DeepEqual(obj, walkFixture()) {
+				t.Errorf("expected the object to be left alone, got %v", obj)
+			}
+		})
+	}
+}